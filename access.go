@@ -0,0 +1,36 @@
+package wh
+
+import "io/fs"
+
+// Access rights recognized by Option.RequireAccess, which may be combined with
+// bitwise OR.
+const (
+	AccessRead    fs.FileMode = 4 // Permission to read a file
+	AccessWrite   fs.FileMode = 2 // Permission to write a file
+	AccessExecute fs.FileMode = 1 // Permission to execute a file
+)
+
+// accessRights returns the access rights in the given mode, which are the
+// rights granted to any class of user (owner, group, or other) in its
+// permission bits. For example, both 0o500 and 0o005 return read and execute.
+func accessRights(mode fs.FileMode) fs.FileMode {
+	perm := mode.Perm()
+	return (perm | perm>>3 | perm>>6) & (AccessRead | AccessWrite | AccessExecute)
+}
+
+// accessible reports whether the current process has each of the access rights
+// specified by option.RequireAccess to the file referred to by the given Link l.
+//
+// Files in a file system other than the host OS are accessible if the required
+// rights are granted to any class of user in the file's permission bits.
+func (option Option) accessible(l *Link) bool {
+	want := accessRights(option.RequireAccess)
+	if want == 0 {
+		return true
+	}
+	if l.fsys == nil {
+		return access(l.Path(), want)
+	}
+	info, err := l.Stat()
+	return err == nil && accessRights(info.Mode())&want == want
+}
@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package wh
+
+import (
+	"io/fs"
+	"os"
+)
+
+// access reports whether the current process has each of the given access
+// rights want to the file at path p, which on this platform is approximated by
+// the rights granted to any class of user in the file's permission bits.
+func access(p string, want fs.FileMode) bool {
+	info, err := os.Stat(p)
+	return err == nil && accessRights(info.Mode())&want == want
+}
@@ -0,0 +1,34 @@
+package wh
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestAccessFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"none": {Mode: 0o000},
+		"r":    {Mode: 0o004},
+		"rw":   {Mode: 0o640},
+		"rx":   {Mode: 0o500},
+	}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	for _, tt := range []struct {
+		access fs.FileMode
+		want   []string
+	}{
+		{0, []string{"none", "r", "rw", "rx"}},
+		{AccessRead, []string{"r", "rw", "rx"}},
+		{AccessWrite, []string{"rw"}},
+		{AccessExecute, []string{"rx"}},
+		{AccessRead | AccessExecute, []string{"rx"}},
+	} {
+		option.RequireAccess = tt.access
+		found, err := MatchFS(fsys, option, "*", ".")
+		expect(t, found, err, tt.want...)
+	}
+}
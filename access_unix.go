@@ -0,0 +1,14 @@
+//go:build unix
+
+package wh
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// access reports whether the current process has each of the given access
+// rights want to the file at path p, as determined by access(2).
+func access(p string, want fs.FileMode) bool {
+	return syscall.Access(p, uint32(want)) == nil
+}
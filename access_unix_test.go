@@ -0,0 +1,42 @@
+//go:build unix
+
+package wh
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestAccessChmod(t *testing.T) {
+	dir := makeTree(t, map[string]string{"none": "", "r": "", "rw": "", "rx": ""})
+	for name, mode := range map[string]fs.FileMode{
+		"none": 0o000, "r": 0o400, "rw": 0o600, "rx": 0o500,
+	} {
+		if err := os.Chmod(filepath.Join(dir, name), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	read, write := []string{"r", "rw", "rx"}, []string{"rw"}
+	if os.Geteuid() == 0 {
+		// The superuser may read and write any file.
+		read, write = []string{"none", "r", "rw", "rx"}, []string{"none", "r", "rw", "rx"}
+	}
+	for _, tt := range []struct {
+		access fs.FileMode
+		want   []string
+	}{
+		{AccessRead, read},
+		{AccessWrite, write},
+		{AccessExecute, []string{"rx"}},
+	} {
+		option.RequireAccess = tt.access
+		found, err := Match(option, "*", dir)
+		expect(t, found, err, join(dir, tt.want...)...)
+	}
+}
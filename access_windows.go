@@ -0,0 +1,56 @@
+//go:build windows
+
+package wh
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// access reports whether the current process has each of the given access
+// rights want to the file at path p.
+//
+// Windows has no equivalent of access(2), so the rights are approximated: a
+// file is readable if it can be opened, writable if it is not read-only, and
+// executable if its extension is listed in the PATHEXT environment variable.
+func access(p string, want fs.FileMode) bool {
+	info, err := os.Stat(p)
+	if err != nil {
+		return false
+	}
+	if want&AccessRead != 0 {
+		f, err := os.Open(p)
+		if err != nil {
+			return false
+		}
+		f.Close()
+	}
+	if want&AccessWrite != 0 && info.Mode().Perm()&0o200 == 0 {
+		return false
+	}
+	if want&AccessExecute != 0 {
+		return executableExt(p)
+	}
+	return true
+}
+
+// executableExt reports whether the extension of the given file name is listed
+// in the PATHEXT environment variable, or in its default value if undefined.
+func executableExt(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == "" {
+		return false
+	}
+	pathExt := os.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = ".com;.exe;.bat;.cmd"
+	}
+	for _, e := range filepath.SplitList(strings.ToLower(pathExt)) {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// Package aho implements the Aho-Corasick string search algorithm, which finds
+// all occurrences of any of a set of patterns in a string in a single pass.
+package aho
+
+// Match describes an occurrence of a pattern in a searched string.
+type Match struct {
+	Pattern int // Index of the pattern in the Automaton
+	Start   int // Byte index of the first byte of the occurrence
+	End     int // Byte index following the last byte of the occurrence
+}
+
+// node is a state of an Automaton.
+type node struct {
+	next map[byte]int // Transition on each byte (trie edges)
+	fail int          // State of the longest proper suffix that is a trie prefix
+	dict int          // Nearest state via fail links that ends a pattern (-1 = none)
+	out  int          // Index of the pattern ending at this state (-1 = none)
+	len  int          // Length of the prefix represented by this state
+}
+
+// Automaton is an Aho-Corasick automaton constructed from a set of patterns.
+// An Automaton is safe to use from multiple goroutines concurrently.
+type Automaton struct {
+	node    []node
+	pattern []string
+}
+
+// New returns an Automaton that finds each of the given patterns.
+// Empty patterns are never found. If a pattern is given more than once, only
+// the index of its first occurrence is reported.
+//
+// The automaton is constructed in time proportional to the total length of
+// all patterns.
+func New(pattern ...string) *Automaton {
+	a := &Automaton{pattern: pattern}
+	a.node = append(a.node, node{next: map[byte]int{}, dict: -1, out: -1})
+	// Build the trie of all patterns.
+	for i, p := range pattern {
+		if p == "" {
+			continue
+		}
+		s := 0
+		for j := 0; j < len(p); j++ {
+			t, ok := a.node[s].next[p[j]]
+			if !ok {
+				t = len(a.node)
+				a.node = append(a.node, node{
+					next: map[byte]int{}, dict: -1, out: -1, len: j + 1})
+				a.node[s].next[p[j]] = t
+			}
+			s = t
+		}
+		if a.node[s].out < 0 {
+			a.node[s].out = i
+		}
+	}
+	// Compute the fail and dict links in breadth-first order, so that the links
+	// of each shorter prefix are known before those of longer prefixes.
+	queue := make([]int, 0, len(a.node))
+	for _, t := range a.node[0].next {
+		queue = append(queue, t)
+	}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for b, t := range a.node[s].next {
+			f := a.node[s].fail
+			for {
+				if u, ok := a.node[f].next[b]; ok && u != t {
+					a.node[t].fail = u
+					break
+				}
+				if f == 0 {
+					break // fail remains the root
+				}
+				f = a.node[f].fail
+			}
+			if u := a.node[t].fail; a.node[u].out >= 0 {
+				a.node[t].dict = u
+			} else {
+				a.node[t].dict = a.node[u].dict
+			}
+			queue = append(queue, t)
+		}
+	}
+	return a
+}
+
+// Patterns returns the patterns found by the receiver Automaton a.
+func (a *Automaton) Patterns() []string { return a.pattern }
+
+// Find calls fn for each occurrence of any pattern in the given string s, in
+// order of the end of each occurrence, until fn returns false.
+// The string is searched in time proportional to its length plus the number
+// of occurrences.
+func (a *Automaton) Find(s string, fn func(m Match) bool) {
+	state := 0
+	for i := 0; i < len(s); i++ {
+		for {
+			if t, ok := a.node[state].next[s[i]]; ok {
+				state = t
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = a.node[state].fail
+		}
+		for d := state; d >= 0; d = a.node[d].dict {
+			if n := a.node[d]; n.out >= 0 {
+				if !fn(Match{Pattern: n.out, Start: i + 1 - n.len, End: i + 1}) {
+					return
+				}
+			}
+			if d == 0 {
+				break
+			}
+		}
+	}
+}
+
+// FindAll returns every occurrence of any pattern in the given string s, in
+// order of the end of each occurrence.
+func (a *Automaton) FindAll(s string) []Match {
+	var m []Match
+	a.Find(s, func(n Match) bool {
+		m = append(m, n)
+		return true
+	})
+	return m
+}
+
+// Contains reports whether any pattern occurs in the given string s.
+func (a *Automaton) Contains(s string) bool {
+	found := false
+	a.Find(s, func(Match) bool {
+		found = true
+		return false
+	})
+	return found
+}
@@ -0,0 +1,56 @@
+package aho
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	a := New("he", "she", "his", "hers", "", "he")
+	if p := a.Patterns(); len(p) != 6 || p[3] != "hers" {
+		t.Fatalf("Patterns() = %q", p)
+	}
+	want := []Match{
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 0, Start: 2, End: 4},
+		{Pattern: 3, Start: 2, End: 6},
+	}
+	if m := a.FindAll("ushers"); !reflect.DeepEqual(m, want) {
+		t.Fatalf("FindAll(\"ushers\") = %v, want %v", m, want)
+	}
+	if m := a.FindAll("xyz"); m != nil {
+		t.Fatalf("FindAll(\"xyz\") = %v, want nil", m)
+	}
+	for s, want := range map[string]bool{"ahis": true, "shh": false, "": false} {
+		if got := a.Contains(s); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestFindNaive(t *testing.T) {
+	pattern := []string{"a", "ab", "bab", "bc", "bca", "c", "caa"}
+	a := New(pattern...)
+	for _, s := range []string{"abccab", "bcaab", "aaaa", "cabcabab"} {
+		var want []Match
+		for end := 1; end <= len(s); end++ {
+			for i, p := range pattern {
+				if strings.HasSuffix(s[:end], p) {
+					want = append(want, Match{Pattern: i, Start: end - len(p), End: end})
+				}
+			}
+		}
+		got := a.FindAll(s)
+		// Occurrences ending at the same index are reported in any order.
+		for _, m := range [][]Match{got, want} {
+			sort.Slice(m, func(i, j int) bool {
+				return m[i].End < m[j].End || m[i].End == m[j].End && m[i].Start < m[j].Start
+			})
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("FindAll(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package wh
+
+import (
+	"strconv"
+
+	"github.com/ardnew/wh/aho"
+	"github.com/ardnew/wh/expr"
+)
+
+// StringSearchAlgo enumerates the algorithms used to match file names with the
+// verbatim string patterns of expr.Fixed and expr.Suffix.
+type StringSearchAlgo int
+
+// Enumerated constants of type StringSearchAlgo.
+const (
+	AlgoNaive       StringSearchAlgo = iota // Compare each pattern directly
+	AlgoKMP                                 // Knuth-Morris-Pratt search for each pattern
+	AlgoAhoCorasick                         // Aho-Corasick search for all patterns at once
+)
+
+// String returns a string representation of the receiver StringSearchAlgo a.
+func (a StringSearchAlgo) String() string {
+	switch a {
+	case AlgoNaive:
+		return "naive"
+	case AlgoKMP:
+		return "kmp"
+	case AlgoAhoCorasick:
+		return "aho-corasick"
+	}
+	return "StringSearchAlgo(" + strconv.Itoa(int(a)) + ")"
+}
+
+// searchable reports whether option.StringAlgo applies to option.Expr.
+func (option Option) searchable() bool {
+	return option.StringAlgo != AlgoNaive &&
+		(option.Expr == expr.Fixed || option.Expr == expr.Suffix)
+}
+
+// algoMatcher returns a matchFunc reporting whether a file name matches any of
+// the given patterns according to option.Expr, using option.StringAlgo.
+// The pattern given to the returned matchFunc is ignored.
+func (option Option) algoMatcher(pattern ...string) matchFunc {
+	// Fixed patterns must span the entire name; Suffix patterns must end it.
+	whole := option.Expr == expr.Fixed
+	if option.StringAlgo == AlgoAhoCorasick {
+		a := aho.New(pattern...)
+		return func(_, name string) (bool, error) {
+			found := false
+			a.Find(name, func(m aho.Match) bool {
+				found = m.End == len(name) && (!whole || m.Start == 0)
+				return !found
+			})
+			return found, nil
+		}
+	}
+	fail := make([][]int, len(pattern))
+	for i, p := range pattern {
+		fail[i] = kmpTable(p)
+	}
+	return func(_, name string) (bool, error) {
+		for i, p := range pattern {
+			found := false
+			kmpFind(name, p, fail[i], func(start int) bool {
+				found = start+len(p) == len(name) && (!whole || start == 0)
+				return !found
+			})
+			if found {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// kmpTable returns the Knuth-Morris-Pratt failure function of pattern p, which
+// is the length of the longest proper prefix of p[:i+1] that is also a suffix.
+func kmpTable(p string) []int {
+	fail := make([]int, len(p))
+	for i, k := 1, 0; i < len(p); i++ {
+		for k > 0 && p[i] != p[k] {
+			k = fail[k-1]
+		}
+		if p[i] == p[k] {
+			k++
+		}
+		fail[i] = k
+	}
+	return fail
+}
+
+// kmpFind calls the given function fn with the index of each occurrence of
+// pattern p in string s, including overlapping occurrences, in order of their
+// end position, using the Knuth-Morris-Pratt failure function fail of p.
+// The search stops when fn returns false.
+func kmpFind(s, p string, fail []int, fn func(start int) bool) {
+	if p == "" {
+		fn(len(s))
+		return
+	}
+	for i, k := 0, 0; i < len(s); i++ {
+		for k > 0 && s[i] != p[k] {
+			k = fail[k-1]
+		}
+		if s[i] == p[k] {
+			k++
+		}
+		if k == len(p) {
+			if !fn(i + 1 - len(p)) {
+				return
+			}
+			k = fail[k-1]
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package wh
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// algoNames are file names matched against patterns using each algorithm.
+var algoNames = []string{
+	"", "a", "aa", "aab", "abab", "ababab", "baab", "go", "gofmt", "go.mod",
+	"main.go", "main_test.go", "gogo", "ogo", "README.md", "Makefile",
+}
+
+func TestStringAlgo(t *testing.T) {
+	pattern := []string{"a", "ab", "abab", "go", "gogo", ".go", "_test.go", "md", "Makefile"}
+	for _, e := range []expr.Expr{expr.Fixed, expr.Suffix} {
+		naive := Option{Expr: e}
+		want, err := naive.anyMatcher(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, algo := range []StringSearchAlgo{AlgoKMP, AlgoAhoCorasick} {
+			option := Option{Expr: e, StringAlgo: algo}
+			got, err := option.anyMatcher(pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range algoNames {
+				w, _ := want("", name)
+				g, _ := got("", name)
+				if g != w {
+					t.Errorf("%s %s: match(%q) = %t, want %t", e, algo, name, g, w)
+				}
+			}
+		}
+	}
+}
+
+func TestKMPFind(t *testing.T) {
+	p := "aba"
+	var start []int
+	kmpFind("abababa", p, kmpTable(p), func(i int) bool {
+		start = append(start, i)
+		return true
+	})
+	if fmt.Sprint(start) != "[0 2 4]" {
+		t.Fatalf("kmpFind() = %v, want [0 2 4]", start)
+	}
+}
+
+func BenchmarkStringAlgo(b *testing.B) {
+	for _, n := range []int{1, 10, 100, 1000} {
+		pattern := make([]string, n)
+		for i := range pattern {
+			pattern[i] = fmt.Sprintf("file-%04d.txt", i)
+		}
+		name := make([]string, 1000)
+		for i := range name {
+			name[i] = fmt.Sprintf("file-%04d.txt", i*7)
+		}
+		for _, algo := range []StringSearchAlgo{AlgoNaive, AlgoKMP, AlgoAhoCorasick} {
+			b.Run(fmt.Sprintf("%s/%d", algo, n), func(b *testing.B) {
+				option := Option{Expr: expr.Suffix, StringAlgo: algo}
+				m, err := option.anyMatcher(pattern)
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					for _, s := range name {
+						_, _ = m("", s)
+					}
+				}
+			})
+		}
+	}
+}
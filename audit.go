@@ -0,0 +1,52 @@
+package wh
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// auditMu serializes writes to each Option.AuditLog, which may be shared by
+// concurrent searches.
+var auditMu sync.Mutex
+
+// auditRecord is the JSON encoding of a single line written to Option.AuditLog.
+type auditRecord struct {
+	Time   time.Time `json:"time"`             // Time the file was accessed
+	Path   string    `json:"path"`             // Path of the file or directory
+	Action string    `json:"action"`           // visited, matched, or skipped
+	Reason string    `json:"reason,omitempty"` // Reason the file was skipped
+}
+
+// auditAction returns the action recorded in Option.AuditLog for an event of
+// the given TraceKind k.
+func auditAction(k TraceKind) string {
+	switch k {
+	case TraceMatch:
+		return "matched"
+	case TraceSkip:
+		return "skipped"
+	}
+	return "visited"
+}
+
+// audit writes a record of the given kind of access to the file at path p to
+// option.AuditLog, if non-nil, as a single line of JSON (NDJSON). Errors
+// writing the record are ignored, so that they do not affect the search.
+func (option Option) audit(kind TraceKind, p string, reason string) {
+	if option.AuditLog == nil {
+		return
+	}
+	b, err := json.Marshal(auditRecord{
+		Time:   time.Now(),
+		Path:   p,
+		Action: auditAction(kind),
+		Reason: reason,
+	})
+	if err != nil {
+		return
+	}
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	option.AuditLog.Write(append(b, '\n'))
+}
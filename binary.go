@@ -0,0 +1,66 @@
+package wh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// MatchBinary returns the result of calling Match with option.BinaryOnly, so
+// that only binary executables (ELF, Mach-O, or PE) are reported.
+func MatchBinary(option Option, pattern string, sub ...string) ([]string, error) {
+	option.BinaryOnly = true
+	return Match(option, option.fold(pattern), sub...)
+}
+
+// Magic numbers at the beginning of binary executable files.
+var (
+	magicELF   = []byte("\x7fELF")
+	magicPE    = []byte("MZ")
+	magicFat   = []byte("\xca\xfe\xba\xbe") // Universal (multi-arch) Mach-O
+	magicMachO = [][]byte{
+		[]byte("\xfe\xed\xfa\xce"), // 32-bit big-endian
+		[]byte("\xce\xfa\xed\xfe"), // 32-bit little-endian
+		[]byte("\xfe\xed\xfa\xcf"), // 64-bit big-endian
+		[]byte("\xcf\xfa\xed\xfe"), // 64-bit little-endian
+	}
+)
+
+// maxFatArch is the greatest number of architectures recognized in a universal
+// Mach-O file. Java class files share its magic number, but are followed by a
+// class file version greater than this.
+const maxFatArch = 30
+
+// headSize is the number of bytes read from the beginning of a file to detect
+// its format, which is enough to contain the longest shebang line recognized by
+// Linux, as well as any binary magic number.
+const headSize = 256
+
+// head returns up to headSize bytes from the beginning of the file referred to
+// by the given Link l, after resolving all symlinks, or nil if the file cannot
+// be read.
+func head(l *Link) []byte {
+	f, err := l.Open()
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	b := make([]byte, headSize)
+	n, _ := io.ReadFull(f, b)
+	return b[:n]
+}
+
+// executableBinary reports whether the given head of a file begins with the
+// magic number of an ELF, Mach-O, or PE binary executable.
+func executableBinary(head []byte) bool {
+	if bytes.HasPrefix(head, magicELF) || bytes.HasPrefix(head, magicPE) {
+		return true
+	}
+	for _, m := range magicMachO {
+		if bytes.HasPrefix(head, m) {
+			return true
+		}
+	}
+	return len(head) >= 8 && bytes.HasPrefix(head, magicFat) &&
+		binary.BigEndian.Uint32(head[4:]) <= maxFatArch
+}
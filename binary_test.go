@@ -0,0 +1,25 @@
+package wh
+
+import "testing"
+
+func TestMatchBinary(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"Tool":      "\x7fELF\x02\x01\x01",
+		"Tool.exe":  "MZ\x90\x00",
+		"tool.sh":   "#!/bin/sh\n",
+		"Java":      "\xca\xfe\xba\xbe\x00\x00\x00\x34",
+		"Universal": "\xca\xfe\xba\xbe\x00\x00\x00\x02",
+	})
+	option := DefaultOption()
+	found, err := MatchBinary(option, "Tool", dir)
+	expect(t, found, err, join(dir, "Tool")...)
+
+	option.IgnoreCase = true
+	found, err = MatchBinary(option, "TOOL", dir)
+	expect(t, found, err, join(dir, "Tool")...)
+
+	found, err = MatchBinary(option, "java", dir)
+	expect(t, found, err)
+	found, err = MatchBinary(option, "UNIVERSAL", dir)
+	expect(t, found, err, join(dir, "Universal")...)
+}
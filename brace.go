@@ -0,0 +1,32 @@
+package wh
+
+import "github.com/ardnew/wh/expr"
+
+// MatchBrace returns the result of calling MatchAny with each of the patterns
+// in the shell brace expansion of the given string pattern (e.g., "{a,b}.txt"
+// expands to "a.txt" and "b.txt"), used to match file names verbatim.
+func MatchBrace(option Option, pattern string, sub ...string) ([]string, error) {
+	option.Expr = expr.Fixed
+	return MatchAny(option, expr.BraceExpand(pattern), sub...)
+}
+
+// braceMatcher returns a matchFunc reporting whether a file name is equal to
+// any of the patterns in the shell brace expansion of any of the given
+// patterns. The pattern given to the returned matchFunc is ignored.
+func (option Option) braceMatcher(pattern ...string) matchFunc {
+	option.Expr = expr.Fixed
+	var alt []string
+	for _, p := range pattern {
+		alt = append(alt, expr.BraceExpand(p)...)
+	}
+	if option.searchable() {
+		return option.algoMatcher(alt...)
+	}
+	set := make(map[string]bool, len(alt))
+	for _, p := range alt {
+		set[p] = true
+	}
+	return func(_, name string) (bool, error) {
+		return set[name], nil
+	}
+}
@@ -0,0 +1,18 @@
+package wh
+
+import "testing"
+
+func TestMatchBrace(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"ac": "", "ad": "", "bc": "", "bd": "", "{a}": "", "e": "",
+	})
+	option := DefaultOption()
+	found, err := MatchBrace(option, "{a,b}{c,d}", dir)
+	expect(t, found, err, join(dir, "ac", "ad", "bc", "bd")...)
+	found, err = MatchBrace(option, "{a}", dir)
+	expect(t, found, err, join(dir, "{a}")...)
+
+	option.StringAlgo = AlgoAhoCorasick
+	found, err = MatchBrace(option, "{b,x}d", dir)
+	expect(t, found, err, join(dir, "bd")...)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ardnew/wh"
+	"github.com/ardnew/wh/expr"
+)
+
+// ANSI escape sequences used to highlight the matching part of a file name.
+const (
+	ansiMatch = "\x1b[1;33m" // Bold yellow
+	ansiReset = "\x1b[0m"
+)
+
+// matchSpan returns the start and end byte index of the part of the given file
+// name that matches the given string pattern according to option.Expr, or -1
+// and -1 if no part matches.
+//
+// Only Regexp and Suffix patterns may match part of a name; all others match
+// the entire name. In particular, path.Match patterns are implicitly anchored at
+// both ends, so a glob translated to an equivalent regular expression would
+// always span the entire name.
+func matchSpan(option wh.Option, pattern, name string) (int, int) {
+	switch option.Expr {
+	case expr.Regexp:
+		if option.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return -1, -1
+		}
+		if loc := re.FindStringIndex(name); loc != nil {
+			return loc[0], loc[1]
+		}
+		return -1, -1
+	case expr.Suffix:
+		return len(name) - len(pattern), len(name)
+	}
+	return 0, len(name)
+}
+
+// colorize returns the given output s describing the given Result r, with the
+// part of its file name matching the given string pattern highlighted in the
+// first line of s.
+func colorize(s string, option wh.Option, pattern string, r wh.Result) string {
+	name := r.Name()
+	line, rest, multi := strings.Cut(s, "\n")
+	i := strings.LastIndex(line, name)
+	if i < 0 {
+		return s
+	}
+	start, end := matchSpan(option, pattern, name)
+	if start < 0 || start >= end || end > len(name) {
+		return s
+	}
+	start, end = i+start, i+end
+	out := line[:start] + ansiMatch + line[start:end] + ansiReset + line[end:]
+	if multi {
+		out += "\n" + rest
+	}
+	return out
+}
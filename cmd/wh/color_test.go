@@ -0,0 +1,43 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ardnew/wh"
+	"github.com/ardnew/wh/expr"
+)
+
+// ansiEscape matches an ANSI SGR (color and style) escape sequence.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripANSI returns the given string s with all ANSI color and style escape
+// sequences removed.
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+func TestColorize(t *testing.T) {
+	for _, tt := range []struct {
+		e       expr.Expr
+		pattern string
+		path    string
+		want    string
+	}{
+		{expr.Fixed, "gofmt", "/usr/bin/gofmt", "/usr/bin/" + ansiMatch + "gofmt" + ansiReset},
+		{expr.Suffix, "fmt", "/usr/bin/gofmt", "/usr/bin/go" + ansiMatch + "fmt" + ansiReset},
+		{expr.Regexp, "o+", "/bin/foo.go", "/bin/f" + ansiMatch + "oo" + ansiReset + ".go"},
+		{expr.Regexp, "x", "/bin/foo", "/bin/foo"},
+	} {
+		option := wh.DefaultOption()
+		option.Expr = tt.e
+		r := wh.Result{Path: tt.path}
+		got := colorize(tt.path, option, tt.pattern, r)
+		if got != tt.want {
+			t.Errorf("colorize(%q, %q) = %q, want %q", tt.pattern, tt.path, got, tt.want)
+		}
+		if stripANSI(got) != tt.path {
+			t.Errorf("stripANSI(%q) = %q, want %q", got, stripANSI(got), tt.path)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// completionCmd is the name of the subcommand that writes a shell completion
+// script to stdout.
+const completionCmd = "completion"
+
+// ErrInvalidShell represents an error in which the completion subcommand was
+// given an unsupported shell name.
+type ErrInvalidShell string
+
+// Error returns a descriptive error string for the receiver ErrInvalidShell e.
+func (e ErrInvalidShell) Error() string {
+	return "unsupported shell: " + strconv.Quote(string(e)) + " (expected bash, zsh, or fish)"
+}
+
+// completionFlag describes a command-line flag for shell completion.
+type completionFlag struct {
+	name  string // Flag name, without leading dash
+	arg   string // Name of the flag's argument, or empty if it has none
+	usage string // Single-line description of the flag
+}
+
+// completionFlags returns a completionFlag for each flag defined in the given
+// flag.FlagSet fs, in lexical order of their names.
+func completionFlags(fs *flag.FlagSet) []completionFlag {
+	var cf []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		arg, usage := flag.UnquoteUsage(f)
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			arg = ""
+		} else if arg == "" {
+			arg = "value"
+		}
+		cf = append(cf, completionFlag{
+			name:  f.Name,
+			arg:   arg,
+			usage: strings.Join(strings.Fields(usage), " "),
+		})
+	})
+	sort.Slice(cf, func(i, j int) bool { return cf[i].name < cf[j].name })
+	return cf
+}
+
+// completion writes a completion script for the shell with the given name to
+// the given io.Writer w, completing each flag defined in the given flag.FlagSet
+// fs. Directories are suggested for the argument of the path flag (-p), and
+// file names are suggested for each positional argument.
+func completion(w io.Writer, fs *flag.FlagSet, shell string) error {
+	cf := completionFlags(fs)
+	switch shell {
+	case "bash":
+		completionBash(w, fs.Name(), cf)
+	case "zsh":
+		completionZsh(w, fs.Name(), cf)
+	case "fish":
+		completionFish(w, fs.Name(), cf)
+	default:
+		return ErrInvalidShell(shell)
+	}
+	return nil
+}
+
+// completionBash writes a completion script for bash(1) to w.
+func completionBash(w io.Writer, cmd string, cf []completionFlag) {
+	var names, args []string
+	for _, f := range cf {
+		names = append(names, "-"+f.name)
+		if f.arg != "" && f.name != "p" {
+			args = append(args, "-"+f.name+"|--"+f.name)
+		}
+	}
+	fn := "_" + cmd
+	fmt.Fprintf(w, "# bash completion for %s\n", cmd)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintf(w, "\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(w, "\tcase \"$prev\" in\n")
+	fmt.Fprintf(w, "\t-p|--p)\n\t\tCOMPREPLY=($(compgen -d -- \"$cur\"))\n\t\treturn\n\t\t;;\n")
+	fmt.Fprintf(w, "\t%s)\n\t\tCOMPREPLY=()\n\t\treturn\n\t\t;;\n", strings.Join(args, "|"))
+	fmt.Fprintf(w, "\tesac\n")
+	fmt.Fprintf(w, "\tif [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shQuote(strings.Join(names, " ")))
+	fmt.Fprintf(w, "\telse\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n")
+	fmt.Fprintf(w, "\tfi\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "complete -o filenames -F %s %s\n", fn, cmd)
+}
+
+// completionZsh writes a completion script for zsh(1) to w.
+func completionZsh(w io.Writer, cmd string, cf []completionFlag) {
+	// zshEscape escapes the characters special to an _arguments spec.
+	zshEscape := strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`, `:`, `\:`)
+	fmt.Fprintf(w, "#compdef %s\n\n", cmd)
+	fmt.Fprintf(w, "_arguments -S \\\n")
+	for _, f := range cf {
+		spec := "-" + f.name + "[" + zshEscape.Replace(f.usage) + "]"
+		switch {
+		case f.name == "p":
+			spec += ":" + zshEscape.Replace(f.arg) + ":_directories"
+		case f.arg != "":
+			spec += ":" + zshEscape.Replace(f.arg) + ": "
+		}
+		fmt.Fprintf(w, "\t%s \\\n", shQuote(spec))
+	}
+	fmt.Fprintf(w, "\t'*:file:_files'\n")
+}
+
+// completionFish writes a completion script for fish(1) to w.
+func completionFish(w io.Writer, cmd string, cf []completionFlag) {
+	fmt.Fprintf(w, "# fish completion for %s\n", cmd)
+	for _, f := range cf {
+		opt := "-o"
+		if len(f.name) == 1 {
+			opt = "-s"
+		}
+		line := fmt.Sprintf("complete -c %s %s %s -d %s", cmd, opt, shQuote(f.name), shQuote(f.usage))
+		switch {
+		case f.name == "p":
+			line += " -x -a '(__fish_complete_directories)'"
+		case f.arg != "":
+			line += " -x"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// shQuote returns the given string s enclosed in single quotes, which is
+// interpreted literally by bash, zsh, and fish.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
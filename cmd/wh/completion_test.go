@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompletionSyntax(t *testing.T) {
+	dir, name := buildWh(t)
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			sh, err := exec.LookPath(shell)
+			if err != nil {
+				t.Skip(shell + " not found")
+			}
+			script, err := exec.Command(filepath.Join(dir, name), completionCmd, shell).Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := filepath.Join(t.TempDir(), "wh."+shell)
+			if err := os.WriteFile(file, script, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(sh, "-n", file).CombinedOutput(); err != nil {
+				t.Fatalf("%s -n: %v\n%s", shell, err, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// exportVar is the prefix of the name of each variable assigned by an exporter.
+const exportVar = "WH_"
+
+// exporter writes each value given to it as a numbered variable assignment in
+// the syntax of some shell, e.g., WH_1="/usr/bin/python3", followed by the
+// total number of values assigned, e.g., WH_COUNT=1.
+type exporter struct {
+	w      io.Writer
+	n      int
+	assign func(name, value string) string
+}
+
+// export writes an assignment of the given value to the next numbered variable.
+func (e *exporter) export(value string) {
+	e.n++
+	fmt.Fprintln(e.w, e.assign(fmt.Sprintf("%s%d", exportVar, e.n), value))
+}
+
+// count writes an assignment of the number of values exported.
+func (e *exporter) count() {
+	fmt.Fprintln(e.w, e.assign(exportVar+"COUNT", fmt.Sprint(e.n)))
+}
+
+// exportSh returns an assignment of value to the variable name in POSIX shell
+// syntax.
+func exportSh(name, value string) string {
+	return name + `="` + shEscape.Replace(value) + `"`
+}
+
+// exportFish returns an assignment of value to the variable name in fish shell
+// syntax.
+func exportFish(name, value string) string {
+	return "set " + name + ` "` + fishEscape.Replace(value) + `";`
+}
+
+// shEscape and fishEscape escape each character that is special inside double
+// quotes in POSIX shell and fish shell, respectively.
+var (
+	shEscape   = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	fishEscape = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`)
+)
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ardnew/wh"
+)
+
+// ErrInvalidField represents an error in which an unrecognized field name was
+// given to the fields flag.
+type ErrInvalidField string
+
+// Error returns a descriptive error string for the receiver ErrInvalidField e.
+func (e ErrInvalidField) Error() string {
+	return "invalid field: " + strconv.Quote(string(e))
+}
+
+// fieldFunc returns the string representation of a single attribute of the
+// file described by r.
+type fieldFunc func(r wh.Result) string
+
+// fieldFuncs maps each field name recognized by the fields flag to the function
+// returning its string representation.
+var fieldFuncs = map[string]fieldFunc{
+	"path":   pathField,
+	"size":   infoField(sizeField),
+	"mtime":  infoField(mtimeField),
+	"perm":   infoField(permField),
+	"type":   infoField(typeField),
+	"depth":  depthField,
+	"chain":  chainField,
+	"module": moduleField,
+}
+
+// FieldFlag contains the functions of each field named in its corresponding
+// command-line flag, in the order given.
+type FieldFlag struct {
+	Name []string
+	Func []fieldFunc
+}
+
+// Set implements the flag.Value interface's Set method.
+// The given string s is a comma-separated list of field names, each of which
+// is added to the receiver in the order given.
+// An error is returned for the first unrecognized field name, if any, or
+// otherwise nil.
+func (f *FieldFlag) Set(s string) error {
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		fn, ok := fieldFuncs[name]
+		if !ok {
+			return ErrInvalidField(name)
+		}
+		f.Name = append(f.Name, name)
+		f.Func = append(f.Func, fn)
+	}
+	return nil
+}
+
+// String returns a descriptive string of the receiver *FieldFlag f.
+func (f *FieldFlag) String() string {
+	return strings.Join(f.Name, ",")
+}
+
+// pathField returns the path of a file.
+func pathField(r wh.Result) string { return r.Path }
+
+// depthField returns the number of path components between a file and the
+// directory in which it was found.
+func depthField(r wh.Result) string { return strconv.Itoa(r.Depth) }
+
+// chainField returns the path of a file followed by the path of each symlink
+// it was dereferenced through.
+func chainField(r wh.Result) string {
+	return r.StringWith(wh.ArrowFormatter{})
+}
+
+// moduleField returns the root directory of the Go module containing a file,
+// if searched with -gomod.
+func moduleField(r wh.Result) string { return r.Module }
+
+// infoField returns a fieldFunc that calls the given function with the
+// fs.FileInfo of a file, or returns "-" if the file cannot be stat'd.
+func infoField(fn func(info fs.FileInfo) string) fieldFunc {
+	return func(r wh.Result) string {
+		info, err := r.Info()
+		if err != nil {
+			return "-"
+		}
+		return fn(info)
+	}
+}
+
+// sizeField returns the size of a file in bytes.
+func sizeField(info fs.FileInfo) string {
+	return strconv.FormatInt(info.Size(), 10)
+}
+
+// mtimeField returns the modification time of a file in RFC 3339 format.
+func mtimeField(info fs.FileInfo) string {
+	return info.ModTime().Format(time.RFC3339)
+}
+
+// permField returns the Unix permission bits of a file (e.g., "-rwxr-xr-x").
+func permField(info fs.FileInfo) string {
+	return info.Mode().Perm().String()
+}
+
+// typeField returns a single letter indicating the type of a file, using the
+// same letters as the -type primary of find(1).
+func typeField(info fs.FileInfo) string {
+	switch m := info.Mode(); {
+	case m.IsDir():
+		return "d"
+	case m&fs.ModeSymlink != 0:
+		return "l"
+	case m&fs.ModeNamedPipe != 0:
+		return "p"
+	case m&fs.ModeSocket != 0:
+		return "s"
+	case m&fs.ModeCharDevice != 0:
+		return "c"
+	case m&fs.ModeDevice != 0:
+		return "b"
+	}
+	return "f"
+}
+
+// formatFields returns each of the given fields of the file described by r,
+// delimited by sep. If no fields are given, the representation of r.Chain
+// rendered by the given ChainFormatter cf is returned.
+func formatFields(r wh.Result, sep string, field []fieldFunc, cf wh.ChainFormatter) string {
+	if len(field) == 0 {
+		return r.StringWith(cf)
+	}
+	t := make([]string, len(field))
+	for i, f := range field {
+		t[i] = f(r)
+	}
+	return strings.Join(t, sep)
+}
+
+// ErrInvalidChainFormat represents an error in which an unrecognized style was
+// given to the chain format flag.
+type ErrInvalidChainFormat string
+
+// Error returns a descriptive error string for the receiver
+// ErrInvalidChainFormat e.
+func (e ErrInvalidChainFormat) Error() string {
+	return "invalid chain format: " + strconv.Quote(string(e))
+}
+
+// chainFormats maps each style recognized by the chain format flag to its
+// corresponding ChainFormatter.
+var chainFormats = map[string]wh.ChainFormatter{
+	"unicode": wh.UnicodeFormatter{},
+	"ascii":   wh.ASCIIFormatter{},
+	"plain":   wh.PlainFormatter{},
+	"arrow":   wh.ArrowFormatter{},
+}
+
+// ChainFormatFlag contains the ChainFormatter named in its corresponding
+// command-line flag.
+type ChainFormatFlag struct {
+	Name string
+	wh.ChainFormatter
+}
+
+// MakeChainFormatFlag returns a ChainFormatFlag initialized with the default
+// style, "unicode".
+func MakeChainFormatFlag() ChainFormatFlag {
+	return ChainFormatFlag{Name: "unicode", ChainFormatter: wh.UnicodeFormatter{}}
+}
+
+// Set implements the flag.Value interface's Set method.
+// An error is returned if the given string s is not a recognized style.
+func (c *ChainFormatFlag) Set(s string) error {
+	f, ok := chainFormats[s]
+	if !ok {
+		return ErrInvalidChainFormat(s)
+	}
+	c.Name, c.ChainFormatter = s, f
+	return nil
+}
+
+// String returns a descriptive string of the receiver *ChainFormatFlag c.
+func (c *ChainFormatFlag) String() string { return c.Name }
+
+// unescape returns a copy of s with each backslash escape sequence recognized
+// by the field separator flag replaced by the character it represents.
+var unescape = strings.NewReplacer(
+	`\\`, `\`, `\t`, "\t", `\n`, "\n", `\0`, "\x00",
+).Replace
@@ -1,6 +1,6 @@
 module github.com/ardnew/wh/cmd/wh
 
-go 1.20
+go 1.21
 
 //replace github.com/ardnew/wh => ../..
 //require github.com/ardnew/wh v0.0.0-00010101000000-000000000000
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ardnew/wh"
+)
+
+// readPathList returns each line of the named file, such as the output of a
+// previous search, as a wh.PathList. Lines may be delimited by newline ('\n')
+// or null ('\0'), as with -0, and empty lines are ignored.
+func readPathList(name string) (wh.PathList, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return strings.FieldsFunc(string(b), func(r rune) bool {
+		return r == '\n' || r == '\r' || r == 0
+	}), nil
+}
+
+// intersect returns a resultFunc that reports each file reported by the given
+// resultFunc fn whose path is also in the given wh.PathList prev.
+func intersect(fn resultFunc, prev wh.PathList) resultFunc {
+	return func(option wh.Option, pattern string, sub ...string) ([]wh.Result, error) {
+		// All files must be found to compute the intersection.
+		limit := option.MaxResults
+		option.MaxResults = 0
+		res, err := fn(option, pattern, sub...)
+		path := make(wh.PathList, len(res))
+		for i, r := range res {
+			path[i] = r.Path
+		}
+		keep := map[string]bool{}
+		for _, p := range path.Intersect(prev) {
+			keep[filepath.Clean(p)] = true
+		}
+		var found []wh.Result
+		for _, r := range res {
+			if keep[filepath.Clean(r.Path)] {
+				found = append(found, r)
+			}
+		}
+		if limit > 0 && len(found) > limit {
+			found = found[:limit]
+		}
+		return found, err
+	}
+}
@@ -1,13 +1,22 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ardnew/wh"
+	"github.com/ardnew/wh/expr"
+	"github.com/ardnew/wh/table"
 )
 
 // ErrNotFound represents an error in which the given file name pattern was not
@@ -35,6 +44,50 @@ func (ErrNoArg) Error() string {
 	return "no search pattern"
 }
 
+// ErrConflictingFlags represents an error in which multiple mutually exclusive
+// command-line flags were given.
+type ErrConflictingFlags struct{ Flags []string }
+
+// Error returns a descriptive error string for the receiver ErrConflictingFlags
+// e.
+func (e ErrConflictingFlags) Error() string {
+	return "conflicting flags: -" + strings.Join(e.Flags, ", -")
+}
+
+// exclusiveFlags contains each group of flags of which at most one may be given.
+var exclusiveFlags = [][]string{
+	{"F", "g", "e", "ext", "z", "fuzzy", "brace"},
+	{"F", "glob-multi", "e", "ext", "z", "fuzzy", "brace"},
+	{"q", "w"},
+	{"export-sh", "export-fish", "j", "table", "T", "c"},
+	{"fields", "j", "T", "c"}, // -table and -export-* print -fields
+}
+
+// exclusive returns an ErrConflictingFlags for the first group of exclusiveFlags
+// of which more than one flag was given, or nil if there is no such group.
+func (fl flags) exclusive() error {
+	for _, group := range exclusiveFlags {
+		var set []string
+		for _, name := range group {
+			if fl.isSet(name) {
+				set = append(set, name)
+			}
+		}
+		if len(set) > 1 {
+			return ErrConflictingFlags{Flags: set}
+		}
+	}
+	return nil
+}
+
+// isSet reports whether the flag with the given name was given on the command
+// line, regardless of its value.
+func (fl flags) isSet(name string) bool {
+	set := false
+	fl.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return set
+}
+
 // PathFlag contains each path found in each occurrence of its corresponding
 // command-line flag.
 type PathFlag struct{ Path []string }
@@ -71,6 +124,177 @@ func (p *PathFlag) String() string {
 	return "[" + strings.Join(t, ", ") + "]"
 }
 
+// ErrInvalidAccess represents an error in which an unrecognized access right
+// was given to the access flag.
+type ErrInvalidAccess string
+
+// Error returns a descriptive error string for the receiver ErrInvalidAccess e.
+func (e ErrInvalidAccess) Error() string {
+	return "invalid access: " + strconv.Quote(string(e))
+}
+
+// AccessFlag contains the access rights named in its corresponding command-line
+// flag.
+type AccessFlag struct{ Mode *fs.FileMode }
+
+// Set implements the flag.Value interface's Set method.
+// The given string s is a combination of the letters r (read), w (write), and
+// x (execute), each of which adds the corresponding right to the receiver.
+// An error is returned for the first unrecognized letter, if any, or otherwise
+// nil.
+func (a AccessFlag) Set(s string) error {
+	var mode fs.FileMode
+	for _, c := range s {
+		switch c {
+		case 'r':
+			mode |= wh.AccessRead
+		case 'w':
+			mode |= wh.AccessWrite
+		case 'x':
+			mode |= wh.AccessExecute
+		default:
+			return ErrInvalidAccess(s)
+		}
+	}
+	*a.Mode = mode
+	return nil
+}
+
+// String returns a descriptive string of the receiver AccessFlag a.
+func (a AccessFlag) String() string {
+	if a.Mode == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, r := range []struct {
+		mode fs.FileMode
+		name byte
+	}{{wh.AccessRead, 'r'}, {wh.AccessWrite, 'w'}, {wh.AccessExecute, 'x'}} {
+		if *a.Mode&r.mode != 0 {
+			sb.WriteByte(r.name)
+		}
+	}
+	return sb.String()
+}
+
+// ErrInvalidType represents an error in which an unrecognized file type was
+// given to the type flag.
+type ErrInvalidType string
+
+// Error returns a descriptive error string for the receiver ErrInvalidType e.
+func (e ErrInvalidType) Error() string {
+	return "invalid type: " + strconv.Quote(string(e))
+}
+
+// typeNames associates each file type with its name in the type flag, which
+// are the same as those of find(1).
+var typeNames = []struct {
+	mask wh.FileTypeMask
+	name string
+}{
+	{wh.TypeRegular, "f"},
+	{wh.TypeDir, "d"},
+	{wh.TypeSymlink, "l"},
+	{wh.TypeDevice, "b"},
+	{wh.TypeDevice, "c"},
+	{wh.TypeNamedPipe, "p"},
+	{wh.TypeSocket, "s"},
+}
+
+// TypeFlag contains the file types named in its corresponding command-line
+// flag.
+type TypeFlag struct{ Mask *wh.FileTypeMask }
+
+// Set implements the flag.Value interface's Set method.
+// The given string s is a comma-separated list of the letters f (regular file),
+// d (directory), l (symlink), b or c (device), p (named pipe), and s (socket).
+// An error is returned for the first unrecognized letter, if any, or otherwise
+// nil.
+func (t TypeFlag) Set(s string) error {
+	var mask wh.FileTypeMask
+	for _, name := range strings.Split(s, ",") {
+		known := false
+		for _, n := range typeNames {
+			if n.name == name {
+				mask |= n.mask
+				known = true
+			}
+		}
+		if !known {
+			return ErrInvalidType(name)
+		}
+	}
+	*t.Mask = mask
+	return nil
+}
+
+// String returns a descriptive string of the receiver TypeFlag t.
+func (t TypeFlag) String() string {
+	if t.Mask == nil {
+		return ""
+	}
+	var name []string
+	var seen wh.FileTypeMask
+	for _, n := range typeNames {
+		if *t.Mask&n.mask != 0 && seen&n.mask == 0 {
+			name = append(name, n.name)
+			seen |= n.mask
+		}
+	}
+	return strings.Join(name, ",")
+}
+
+// ErrNotRelative represents an error in which the path of a matching file could
+// not be made relative to the directory given to the relative path flag.
+type ErrNotRelative string
+
+// Error returns a descriptive error string for the receiver ErrNotRelative e.
+func (e ErrNotRelative) Error() string {
+	return "cannot make path relative: " + string(e)
+}
+
+// ErrInvalidTime represents an error in which a string given to a time flag is
+// neither a timestamp in the expected layout nor a duration.
+type ErrInvalidTime string
+
+// Error returns a descriptive error string for the receiver ErrInvalidTime e.
+func (e ErrInvalidTime) Error() string {
+	return "invalid time: " + strconv.Quote(string(e))
+}
+
+// parseTime returns the time represented by the given string s, which is either
+// a timestamp in the given layout or a duration (e.g., "24h") relative to now,
+// in which case the time is that duration before now.
+func parseTime(s, layout string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(layout, s); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, ErrInvalidTime(s)
+}
+
+// ListFlag contains each string given in each occurrence of its corresponding
+// command-line flag.
+type ListFlag []string
+
+// Set implements the flag.Value interface's Set method.
+// The given string s is appended to the receiver slice verbatim.
+func (l *ListFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// String returns a descriptive string of the receiver *ListFlag l.
+func (l *ListFlag) String() string {
+	t := make([]string, len(*l))
+	for i, s := range *l {
+		t[i] = fmt.Sprintf("%q", s)
+	}
+	return "[" + strings.Join(t, ", ") + "]"
+}
+
 type flags struct {
 	*flag.FlagSet
 	dir PathFlag
@@ -79,51 +303,244 @@ type flags struct {
 
 func main() {
 
-	fl := flags{FlagSet: flag.NewFlagSet("wh", flag.ContinueOnError), dir: MakePathFlag()}
+	fl := flags{FlagSet: flag.NewFlagSet("wh", flag.ContinueOnError), dir: MakePathFlag(), opt: wh.DefaultOption()}
 	fl.Usage = fl.PrintDefaults
 
-	var fixedFlag, globFlag, regexpFlag bool
-	var allFlag, nullFlag, quietFlag, warnFlag bool
+	var fixedFlag, globFlag, regexpFlag, fuzzyFlag, braceFlag bool
+	var allFlag, nullFlag, quietFlag, warnFlag, zipFlag, notFlag, verboseFlag, countFlag bool
+	var exportShFlag, exportFishFlag, jsonFlag, multiFlag, tableFlag, colorFlag bool
+	var fieldFlag FieldFlag
+	var extFlag, globMultiFlag string
+	chainFlag := MakeChainFormatFlag()
+	var sepFlag, userFlag, groupFlag string
+	var newerFlag, olderFlag, timeLayoutFlag, patternFileFlag, templateFlag, auditFlag, intersectFlag string
 
 	fl.BoolVar(&fl.opt.FollowSymlinks, "L", false, "Follow symbolic links")
+	fl.BoolVar(&fl.opt.Unique, "u", false, "Report each file only once, if found through multiple symbolic links")
+	fl.BoolVar(&fl.opt.SymlinksAsFiles, "symlinks-as-files", false, "Match symbolic links by name without following them (overrides -L)")
 	fl.IntVar(&fl.opt.MaxFollow, "s", 0, "Dereference up to `count` chains of symbolic links (-1 = unlimited)")
 	fl.IntVar(&fl.opt.MaxDepth, "d", 1, "Limit directory traversal to `depth` levels")
+	fl.IntVar(&fl.opt.Parallel, "parallel", 0, "Search up to `count` directories in path-list concurrently")
+	fl.BoolVar(&fl.opt.ParallelReadDir, "parallel-readdir", false, "Read subdirectories concurrently while searching each directory")
+	fl.BoolVar(&fl.opt.PreserveOrder, "preserve-order", false, "Report files in path-list order when searching concurrently")
+	fl.IntVar(&fl.opt.MaxDirs, "max-dirs", 0, "Stop searching after `count` directories (0 = unlimited)")
+	fl.Int64Var(&fl.opt.MinSize, "min-size", 0, "Report only files of at least `bytes` in size (0 = no minimum)")
+	fl.Int64Var(&fl.opt.MaxSize, "max-size", 0, "Report only files of at most `bytes` in size (0 = no maximum)")
 	fl.BoolVar(&fixedFlag, "F", true, "Use fixed string matching")
 	fl.BoolVar(&globFlag, "g", false, "Use glob pattern matching")
+	fl.StringVar(&globMultiFlag, "glob-multi", "", "Use glob pattern matching with each pattern in comma-separated `list`")
 	fl.BoolVar(&regexpFlag, "e", false, "Use regular expression pattern matching")
+	fl.StringVar(&patternFileFlag, "f", "", "Also search for each pattern on a line in `file` (- = stdin), ignoring empty lines and lines beginning with #")
+	fl.BoolVar(&fuzzyFlag, "z", false, "Use fuzzy matching (same as -fuzzy)")
+	fl.BoolVar(&fuzzyFlag, "fuzzy", false, "Use fuzzy matching: names within -fuzzy-threshold edits of the pattern")
+	fl.BoolVar(&braceFlag, "brace", false, "Use fixed string matching of each shell brace expansion of the pattern (e.g., {a,b}.txt)")
+	fl.IntVar(&fl.opt.FuzzyThreshold, "fuzzy-threshold", expr.FuzzyThreshold, "Match names within `distance` edits of the pattern with -fuzzy")
+	fl.StringVar(&extFlag, "ext", "", "Match file names ending with any extension in comma-separated `list` (e.g., go,.pb.go; other patterns match the end of names verbatim)")
+	fl.BoolVar(&multiFlag, "multi", false, "Search each directory once for files matching any pattern")
+	fl.StringVar(&intersectFlag, "intersect", "", "Report only files also listed in `file`, such as the output of a previous search")
+	fl.BoolVar(&notFlag, "not", false, "Report all files that do not match")
 	fl.BoolVar(&fl.opt.IgnoreCase, "i", false, "Use case-insensitive matching")
 	fl.BoolVar(&allFlag, "a", false, "Report all matching files")
+	fl.BoolVar(&countFlag, "c", false, "Print only the number of matching files (implies -a)")
 	fl.BoolVar(&nullFlag, "0", false, "Delimit output with null ('\\0') instead of newline ('\\n')")
 	fl.BoolVar(&quietFlag, "q", false, "Print nothing; status indicates match found")
 	fl.BoolVar(&warnFlag, "w", false, "Print warning and diagnostic messages")
+	fl.BoolVar(&verboseFlag, "v", false, "Print a summary of the search after all matching files")
 	fl.Var(&fl.dir, "p", "Search only in `path-list` (can be specified multiple times)")
+	fl.StringVar(&fl.opt.RequiredParentDir, "in-dir", "", "Report only files whose parent directory is named `name`")
+	fl.Var((*ListFlag)(&fl.opt.Exclude), "x", "Ignore files whose name matches `pattern` (can be specified multiple times)")
+	fl.Var((*ListFlag)(&fl.opt.AllowedDirs), "allow-dir", "Descend only into subdirectories named `name` (can be specified multiple times)")
+	fl.BoolVar(&fl.opt.SkipHidden, "skip-hidden", false, "Ignore hidden files and directories")
+	fl.BoolVar(&fl.opt.HiddenOnly, "hidden-only", false, "Report only hidden files in hidden directories")
+	fl.StringVar(&fl.opt.ContentPattern, "content", "", "Report only files containing a line matching `regexp`")
+	fl.BoolVar(&zipFlag, "zip-content", false, "Also search the files contained in each ZIP archive found")
+	fl.StringVar(&fl.opt.RelativeTo, "r", "", "Print the path of each matching file relative to directory `dir` (e.g., .)")
+	fl.BoolVar(&fl.opt.AbsolutePaths, "print-abs", false, "Print the absolute path of each matching file")
+	fl.BoolVar(&fl.opt.GoModuleAware, "gomod", false, "Limit traversal depth (-d) relative to each directory containing a go.mod file, and print its path before each file found in it")
+	fl.BoolVar(&fl.opt.BreadthFirst, "breadth-first", false, "Search all files at each depth before descending into subdirectories")
+	fl.BoolVar(&fl.opt.SkipMountPoints, "skip-mounts", false, "Do not descend into directories on other devices (mount points)")
+	fl.BoolVar(&fl.opt.GitStagedOnly, "git-staged", false, "Report only files staged in the git index of the working directory")
+	fl.BoolVar(&fl.opt.GitTrackedOnly, "git-tracked", false, "Report only files tracked (or untracked but not ignored) by git")
+	fl.BoolVar(&fl.opt.DanglingOnly, "dangling", false, "Report only symbolic links whose target does not exist")
+	fl.BoolVar(&fl.opt.HardlinksOnly, "hardlinks", false, "Report only files with more than one hard link")
+	fl.StringVar(&fl.opt.ScriptInterpreter, "shebang", "", "Report only scripts whose shebang (#!) line contains `interpreter`")
+	fl.BoolVar(&fl.opt.ExecutableOnly, "X", false, "Report only executable files")
+	fl.Var(TypeFlag{Mask: &fl.opt.FileTypes}, "type", "Report only files of comma-separated `types` f (file), d (directory), l (symlink), b or c (device), p (pipe), or s (socket)")
+	fl.Var(AccessFlag{Mode: &fl.opt.RequireAccess}, "access", "Report only files with access `rights` r, w, x, or any combination thereof")
+	fl.StringVar(&newerFlag, "newer", "", "Report only files modified after `timestamp` (in -time-layout) or within a duration (e.g., 24h)")
+	fl.StringVar(&olderFlag, "older", "", "Report only files modified before `timestamp` (in -time-layout) or longer than a duration (e.g., 24h) ago")
+	fl.StringVar(&timeLayoutFlag, "time-layout", time.RFC3339, "Parse -newer and -older timestamps with Go time `layout`")
+	fl.StringVar(&userFlag, "user", "", "Report only files owned by user `name` (Unix only)")
+	fl.StringVar(&groupFlag, "group", "", "Report only files owned by group `name` (Unix only)")
+	fl.BoolVar(&fl.opt.Sort, "sort", true, "Search directory entries in lexical order")
+	fl.StringVar(&fl.opt.SortBy, "sort-by", "", "Report files sorted by `field` name, path, size, or mtime instead of in the order found")
+	fl.BoolVar(&tableFlag, "table", false, "Print all matching files as a table of -fields (default path,size,mtime,perm,depth)")
+	fl.StringVar(&templateFlag, "T", "", "Print each matching file by executing Go text/`template` (e.g., {{.Path}}) with fields Path, Name, Dir, Size, ModTime, Mode, IsSymlink, IsExecutable")
+	fl.BoolVar(&colorFlag, "color", false, "Highlight the matching part of each file name if stdout is a terminal and NO_COLOR is not set")
+	fl.BoolVar(&jsonFlag, "j", false, "Print all matching files as a JSON array of strings")
+	fl.BoolVar(&exportShFlag, "export-sh", false, "Print each matching file as a POSIX shell variable assignment (WH_1, WH_2, ..., WH_COUNT)")
+	fl.BoolVar(&exportFishFlag, "export-fish", false, "Print each matching file as a fish shell variable assignment (WH_1, WH_2, ..., WH_COUNT)")
+	fl.Var(&fieldFlag, "fields", "Print the comma-separated `list` of fields for each matching file (path,size,mtime,perm,type,depth,chain,module)")
+	fl.Var(&chainFlag, "chain-format", "Render symbolic link chains in `style` unicode, ascii, plain, or arrow")
+	fl.StringVar(&sepFlag, "field-sep", `\t`, "Delimit printed fields with `sep` (recognizes \\t, \\n, and \\0)")
+	fl.StringVar(&auditFlag, "audit", "", "Append a JSON record of each file accessed to `file`, even with -q")
+	fl.StringVar(&fl.opt.EnvVar, "env", "PATH", "Search in path-list from environment `variable` if -p not given")
 
 	var errWriter, outWriter io.Writer = os.Stderr, os.Stdout
 
+	if len(os.Args) > 1 && os.Args[1] == completionCmd {
+		if len(os.Args) != 3 {
+			halt(errWriter, ErrInvalidShell(strings.Join(os.Args[2:], " ")))
+		}
+		halt(errWriter, completion(outWriter, fl.FlagSet, os.Args[2]))
+		return
+	}
+
+	// Options in the config file, if any, are overridden by command-line flags.
+	if p, ok := configPath(); ok {
+		opt, err := wh.LoadConfig(p)
+		if err == nil {
+			fl.opt = opt
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			halt(errWriter, err)
+		}
+	}
+
 	if err := fl.Parse(os.Args[1:]); err != nil {
 		halt(errWriter, err)
 	}
 
+	if err := fl.exclusive(); err != nil {
+		halt(errWriter, err)
+	}
+
 	if quietFlag {
 		errWriter = io.Discard
 		outWriter = io.Discard
 	}
 	fl.SetOutput(outWriter)
 
+	if auditFlag != "" {
+		f, err := os.OpenFile(auditFlag, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			halt(errWriter, err)
+		}
+		defer f.Close()
+		fl.opt.AuditLog = f
+	}
+
 	eol := "\n"
 	if nullFlag {
 		eol = "\x00"
 	}
 
-	if len(fl.Args()) == 0 {
+	sep := unescape(sepFlag)
+
+	args := fl.Args()
+	if extFlag != "" {
+		// Each extension is matched as a suffix beginning with '.'.
+		var ext []string
+		for _, e := range strings.Split(extFlag, ",") {
+			if e = strings.TrimSpace(e); e == "" {
+				continue
+			}
+			if !strings.HasPrefix(e, ".") {
+				e = "." + e
+			}
+			ext = append(ext, e)
+		}
+		args = append(ext, args...)
+	}
+	if globMultiFlag != "" {
+		var glob []string
+		for _, g := range strings.Split(globMultiFlag, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				glob = append(glob, g)
+			}
+		}
+		args = append(glob, args...)
+	}
+	if patternFileFlag != "" {
+		pattern, err := loadPatterns(patternFileFlag)
+		if err != nil {
+			halt(errWriter, err)
+		}
+		args = append(args, pattern...)
+	}
+
+	if len(args) == 0 {
 		halt(errWriter, ErrNoArg(true), fl.PrintDefaults)
 	}
 
-	fn := wh.MatchFixed
-	if regexpFlag {
-		fn = wh.MatchRegexp
-	} else if globFlag {
-		fn = wh.MatchGlob
+	// Read each pattern from stdin if it is the only argument and not a terminal.
+	streamFlag := len(args) == 1 && args[0] == "-" && !isTerminal(os.Stdin)
+
+	if fl.isSet("F") {
+		fl.opt.Expr = expr.Fixed
+	}
+	if extFlag != "" {
+		fl.opt.Expr = expr.Suffix
+	} else if regexpFlag {
+		fl.opt.Expr = expr.Regexp
+	} else if fuzzyFlag {
+		fl.opt.Expr = expr.Fuzzy
+	} else if braceFlag {
+		fl.opt.Expr = expr.BraceExpr
+	} else if globFlag || globMultiFlag != "" {
+		fl.opt.Expr = expr.Glob
+	}
+
+	if countFlag {
+		allFlag = true
+	}
+	if !allFlag {
+		// Stop searching as soon as the first match is found.
+		fl.opt.MaxResults = 1
+	}
+
+	now := time.Now()
+	if newerFlag != "" {
+		t, err := parseTime(newerFlag, timeLayoutFlag, now)
+		if err != nil {
+			halt(errWriter, err)
+		}
+		fl.opt.After = t
+	}
+	if olderFlag != "" {
+		t, err := parseTime(olderFlag, timeLayoutFlag, now)
+		if err != nil {
+			halt(errWriter, err)
+		}
+		fl.opt.Before = t
+	}
+
+	if userFlag != "" {
+		if err := wh.WithOwnerName(userFlag)(&fl.opt); err != nil {
+			halt(errWriter, err)
+		}
+	}
+	if groupFlag != "" {
+		if err := wh.WithGroupName(groupFlag)(&fl.opt); err != nil {
+			halt(errWriter, err)
+		}
+	}
+
+	// Report an invalid template before searching.
+	if templateFlag != "" {
+		if err := wh.FormatResult(io.Discard, templateFlag, "."); err != nil {
+			halt(errWriter, err)
+		}
+	}
+
+	var export *exporter
+	if exportShFlag {
+		export = &exporter{w: outWriter, assign: exportSh}
+	} else if exportFishFlag {
+		export = &exporter{w: outWriter, assign: exportFish}
+	}
+
+	if err := fl.opt.Validate(); err != nil {
+		halt(errWriter, err)
 	}
 
 	fl.opt.WorkingDir = "."
@@ -132,21 +549,27 @@ func main() {
 	}
 
 	if fl.dir.Len() == 0 {
-		var err error
-		if p, ok := os.LookupEnv("PATH"); ok {
-			err = fl.dir.Set(p)
-		} else {
-			err = fl.dir.Set(fl.opt.WorkingDir)
+		dirs := wh.DefaultSearchDirs()
+		if p, err := wh.FromEnv(fl.opt.EnvVar); err == nil && fl.opt.EnvVar != "PATH" {
+			dirs = p.Dirs()
 		}
-		if err != nil {
-			halt(errWriter, err)
+		for _, d := range dirs {
+			if err := fl.dir.Set(d); err != nil {
+				halt(errWriter, err)
+			}
 		}
 	}
 
-	found := []string{}
+	var stats wh.MatchStats
+	if verboseFlag {
+		fl.opt.Stats = &stats
+	}
+	start := time.Now()
+
+	found := []wh.Result{}
+	count := 0 // Number of files counted but not found, if -c
 	warns := []error{}
-	for _, a := range fl.Args() {
-		f, err := fn(fl.opt, a, fl.dir.Path...)
+	report := func(err error) {
 		if err != nil {
 			warn := fmt.Errorf("warning: %w", err)
 			if warnFlag {
@@ -155,25 +578,239 @@ func main() {
 				warns = append(warns, warn)
 			}
 		}
+	}
+
+	var archives []string
+	if zipFlag {
+		var err error
+		archives, err = findArchives(fl.opt, fl.dir.Path...)
+		report(err)
+	}
+
+	var prev wh.PathList
+	if intersectFlag != "" {
+		var err error
+		if prev, err = readPathList(intersectFlag); err != nil {
+			halt(errWriter, err)
+		}
+	}
+
+	var fn resultFunc = wh.MatchResults
+	if notFlag {
+		fn = negate(fn)
+	}
+	if intersectFlag != "" {
+		fn = intersect(fn, prev)
+	}
+
+	search := func(a string) []wh.Result {
+		f, err := fn(fl.opt, a, fl.dir.Path...)
+		report(err)
+		if zipFlag {
+			z, err := matchArchives(fl.opt, a, archives)
+			report(err)
+			f = append(f, z...)
+		}
+		return f
+	}
+
+	var results wh.Results
+	var rows []wh.Result
+	color := colorFlag && isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	emit := func(f []wh.Result) {
+		if countFlag {
+			return // Only the number of files is printed.
+		}
+		for _, r := range f {
+			if fl.opt.RelativeTo != "" && filepath.IsAbs(r.Path) {
+				report(ErrNotRelative(r.Path))
+			}
+			if templateFlag != "" {
+				if err := formatResult(outWriter, templateFlag, r, archives); err != nil {
+					halt(errWriter, err)
+				}
+				fmt.Fprint(outWriter, eol)
+				continue
+			}
+			if jsonFlag {
+				results = append(results, r.Path)
+				continue
+			}
+			if tableFlag {
+				rows = append(rows, r)
+				continue
+			}
+			if export != nil {
+				if len(fieldFlag.Func) == 0 {
+					export.export(r.String())
+				} else {
+					export.export(formatFields(r, sep, fieldFlag.Func, chainFlag))
+				}
+				continue
+			}
+			out := formatFields(r, sep, fieldFlag.Func, chainFlag)
+			if color && len(fieldFlag.Func) == 0 {
+				out = colorize(out, fl.opt, r.MatchedPattern, r)
+			}
+			if r.Module != "" && len(fieldFlag.Func) == 0 {
+				out = r.Module + sep + out
+			}
+			fmt.Fprintf(outWriter, "%s%s", out, eol)
+		}
+	}
+
+	if streamFlag {
+		// Print the files found for each pattern before reading the next pattern,
+		// reporting only the first file found for each pattern unless -a.
+		args = args[:0]
+		scan := bufio.NewScanner(os.Stdin)
+		for scan.Scan() {
+			a := strings.TrimSpace(scan.Text())
+			if a == "" {
+				continue
+			}
+			args = append(args, a)
+			f := search(a)
+			if !allFlag && len(f) > 0 {
+				f = f[0:1]
+			}
+			emit(f)
+			found = append(found, f...)
+		}
+		report(scan.Err())
+	} else if multiFlag {
+		// Search for all patterns at once.
+		var anyFn resultFunc = func(o wh.Option, _ string, sub ...string) ([]wh.Result, error) {
+			return wh.MatchAnyResults(o, args, sub...)
+		}
+		if notFlag {
+			anyFn = negate(anyFn)
+		}
+		if intersectFlag != "" {
+			anyFn = intersect(anyFn, prev)
+		}
+		f, err := anyFn(fl.opt, "", fl.dir.Path...)
+		report(err)
+		if zipFlag {
+			for _, a := range args {
+				z, err := matchArchives(fl.opt, a, archives)
+				report(err)
+				f = append(f, z...)
+			}
+		}
 		if !allFlag && len(f) > 0 {
-			found = f[0:1]
-			break
+			f = f[0:1]
+		}
+		found = f
+	} else if countFlag && !notFlag && intersectFlag == "" {
+		// Count the files found without building the Result of each.
+		for _, a := range args {
+			n, err := wh.Count(fl.opt, foldCase(fl.opt, a), fl.dir.Path...)
+			report(err)
+			count += n
+			if zipFlag {
+				z, err := matchArchives(fl.opt, a, archives)
+				report(err)
+				count += len(z)
+			}
+		}
+	} else {
+		for _, a := range args {
+			f := search(a)
+			if !allFlag && len(f) > 0 {
+				found = f[0:1]
+				break
+			}
+			found = append(found, f...)
+		}
+	}
+
+	// Print the JSON array after all files are found, even if none were found.
+	printJSON := func() {
+		if jsonFlag {
+			if err := json.NewEncoder(outWriter).Encode(results); err != nil {
+				halt(errWriter, err)
+			}
+		}
+	}
+
+	if countFlag {
+		count += len(found)
+		fmt.Fprintln(outWriter, count)
+		if count == 0 {
+			os.Exit(1)
 		}
-		found = append(found, f...)
+		return
 	}
 
 	if len(found) == 0 {
+		printJSON()
 		if !warnFlag {
 			for _, w := range warns {
 				fmt.Fprintln(errWriter, w)
 			}
 		}
-		halt(errWriter, ErrNotFound(fl.Args()))
+		halt(errWriter, ErrNotFound(args))
+	}
+
+	if !streamFlag {
+		emit(found)
+	}
+	if export != nil {
+		export.count()
+	}
+	printJSON()
+	if tableFlag {
+		if err := table.Table(rows, fieldFlag.Name, outWriter); err != nil {
+			halt(errWriter, err)
+		}
+	}
+
+	if verboseFlag {
+		fmt.Fprintf(errWriter, "Searched %d directories, found %d matches in %dms\n",
+			stats.DirsScanned, len(found), time.Since(start).Milliseconds())
+	}
+}
+
+// foldCase returns the given string pattern modified, as by wh.MatchResults, to
+// match file names regardless of case if option.IgnoreCase is true.
+func foldCase(option wh.Option, pattern string) string {
+	if option.IgnoreCase {
+		if option.Expr == expr.Regexp {
+			return "(?i)" + pattern
+		}
+		return strings.ToLower(pattern)
 	}
+	return pattern
+}
 
-	for _, f := range found {
-		fmt.Fprintf(outWriter, "%s%s", f, eol)
+// loadPatterns returns the patterns read by wh.LoadPatterns from the named file,
+// or from stdin if name is "-".
+func loadPatterns(name string) ([]string, error) {
+	if name == "-" {
+		return wh.LoadPatterns(os.Stdin)
 	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return wh.LoadPatterns(f)
+}
+
+// configPath returns the path of the config file, which is wh/config.json in
+// $XDG_CONFIG_HOME or, if undefined, in ~/.config, and whether or not the path
+// could be determined.
+func configPath() (string, bool) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "wh", "config.json"), true
 }
 
 func halt(w io.Writer, err error, final ...func()) {
@@ -189,12 +826,14 @@ func halt(w io.Writer, err error, final ...func()) {
 		switch err.(type) {
 		case ErrNotFound:
 			os.Exit(1)
-		case ErrNoArg:
+		case ErrNoArg, ErrConflictingFlags, ErrInvalidShell, wh.ErrNoPatterns:
 			os.Exit(2)
 		case wh.ErrWalkDir:
 			os.Exit(3)
 		case wh.ErrInvalidPath:
 			os.Exit(4)
+		case wh.ErrConflictingOptions:
+			os.Exit(2)
 		default:
 			if err == flag.ErrHelp {
 				os.Exit(0)
@@ -203,3 +842,10 @@ func halt(w io.Writer, err error, final ...func()) {
 		}
 	}
 }
+
+// isTerminal reports whether the given file f is a character device, such as
+// an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
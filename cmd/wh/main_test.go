@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ardnew/wh"
+)
+
+// buildWh builds the wh command into a new temporary directory and returns the
+// directory and the base name of the executable.
+func buildWh(t *testing.T) (dir, name string) {
+	t.Helper()
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	dir, name = t.TempDir(), "wh"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	build := exec.Command(goBin, "build", "-o", filepath.Join(dir, name), ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return dir, name
+}
+
+func TestFindSelf(t *testing.T) {
+	dir, name := buildWh(t)
+	found, err := wh.MatchFixed(wh.DefaultOption(), name, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, name); len(found) != 1 || found[0] != want {
+		t.Fatalf("MatchFixed(%q) = %q, want [%q]", name, found, want)
+	}
+}
+
+func TestMalformedTemplate(t *testing.T) {
+	dir, name := buildWh(t)
+	var stdout, stderr strings.Builder
+	cmd := exec.Command(filepath.Join(dir, name), "-p", dir, "-T", "{{.Path", name)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatal("malformed template did not fail")
+	}
+	if stdout.Len() != 0 || !strings.Contains(stderr.String(), "template") {
+		t.Fatalf("stdout %q, stderr %q, want only template error", stdout.String(), stderr.String())
+	}
+}
+
+func TestConflictingFlags(t *testing.T) {
+	dir, name := buildWh(t)
+	for _, arg := range [][]string{
+		{"-T", "{{.Path}}", "-fields", "size,path"},
+		{"-j", "-fields", "path"},
+		{"-c", "-T", "{{.Path}}"},
+	} {
+		var stdout, stderr strings.Builder
+		cmd := exec.Command(filepath.Join(dir, name), append(append([]string{"-p", dir}, arg...), name)...)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err == nil {
+			t.Errorf("wh %q did not fail", arg)
+		}
+		if stdout.Len() != 0 || !strings.Contains(stderr.String(), "conflicting flags") {
+			t.Errorf("wh %q: stdout %q, stderr %q, want conflicting flags", arg, stdout.String(), stderr.String())
+		}
+	}
+	// -table prints the -fields given.
+	out, err := exec.Command(filepath.Join(dir, name), "-p", dir, "-table", "-fields", "depth", name).Output()
+	if err != nil || string(out) != "DEPTH\n1\n" {
+		t.Fatalf("wh -table -fields depth = %q, %v", out, err)
+	}
+}
+
+func TestCountFlag(t *testing.T) {
+	bin, name := buildWh(t)
+	dir := t.TempDir()
+	for _, f := range []string{"a.go", "B.GO", "c.txt", "sub/d.go"} {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tc := range []struct {
+		arg  []string
+		want string
+		code int
+	}{
+		{[]string{"-g", "*.go"}, "1\n", 0},
+		{[]string{"-g", "-i", "*.go"}, "2\n", 0},
+		{[]string{"-g", "-i", "-d", "2", "*.go", "*.txt"}, "4\n", 0},
+		{[]string{"-e", "-i", `^b\.`}, "1\n", 0},
+		{[]string{"-not", "-g", "*.go"}, "2\n", 0},
+		{[]string{"-g", "*.rs"}, "0\n", 1},
+	} {
+		cmd := exec.Command(filepath.Join(bin, name), append([]string{"-p", dir, "-c"}, tc.arg...)...)
+		out, err := cmd.Output()
+		if string(out) != tc.want || cmd.ProcessState.ExitCode() != tc.code {
+			t.Errorf("wh -c %q = %q, %v, want %q and exit status %d", tc.arg, out, err, tc.want, tc.code)
+		}
+	}
+}
+
+func TestExtFlag(t *testing.T) {
+	bin, name := buildWh(t)
+	dir := t.TempDir()
+	for _, f := range []string{"a.go", "b.pb.go", "c.txt", "ctxt", "d.go.sum"} {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tc := range []struct {
+		arg  []string
+		want []string
+	}{
+		{[]string{"-ext", "go"}, []string{"a.go", "b.pb.go"}},
+		{[]string{"-ext", ".go"}, []string{"a.go", "b.pb.go"}},
+		{[]string{"-ext", " .pb.go, txt ,"}, []string{"b.pb.go", "c.txt"}},
+		{[]string{"-ext", "sum", "txt"}, []string{"d.go.sum", "c.txt", "ctxt"}},
+	} {
+		arg := append([]string{"-p", dir, "-a"}, tc.arg...)
+		out, err := exec.Command(filepath.Join(bin, name), arg...).Output()
+		var want strings.Builder
+		for _, w := range tc.want {
+			want.WriteString(filepath.Join(dir, w) + "\n")
+		}
+		if string(out) != want.String() {
+			t.Errorf("wh %q = %q, %v, want %q", tc.arg, out, err, want.String())
+		}
+	}
+}
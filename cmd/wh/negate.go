@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/ardnew/wh"
+	"github.com/ardnew/wh/expr"
+)
+
+// resultFunc is the signature of the function used to search for each pattern.
+type resultFunc func(wh.Option, string, ...string) ([]wh.Result, error)
+
+// negate returns a resultFunc that reports each file found in the searched
+// directories that is not reported by the given resultFunc fn.
+// It is the equivalent of wh.Negate for functions returning wh.Result.
+func negate(fn resultFunc) resultFunc {
+	return func(option wh.Option, pattern string, sub ...string) ([]wh.Result, error) {
+		// Both searches must be complete to compute the difference.
+		limit := option.MaxResults
+		option.MaxResults = 0
+		aopt := option
+		aopt.Expr = expr.Glob
+		all, aerr := wh.MatchResults(aopt, "*", sub...)
+		exc, ferr := fn(option, pattern, sub...)
+		if ferr != nil && exc == nil {
+			return nil, ferr
+		}
+		skip := make(map[string]bool, len(exc))
+		for _, r := range exc {
+			skip[r.String()] = true
+		}
+		var found []wh.Result
+		for _, r := range all {
+			if !skip[r.String()] {
+				found = append(found, r)
+			}
+		}
+		if limit > 0 && len(found) > limit {
+			found = found[:limit]
+		}
+		if ferr != nil {
+			return found, ferr
+		}
+		return found, aerr
+	}
+}
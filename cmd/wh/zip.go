@@ -0,0 +1,81 @@
+package main
+
+import (
+	"io"
+
+	"github.com/ardnew/wh"
+	"github.com/ardnew/wh/expr"
+)
+
+// findArchives returns the path of each ZIP archive found in each of the given
+// directories sub according to the traversal options of the given search
+// options. The filters applied to matching files (e.g., -X, -x, -size, -type)
+// are not applied to the archives themselves.
+func findArchives(option wh.Option, sub ...string) ([]string, error) {
+	search := wh.DefaultOption()
+	search.MaxFollow = option.MaxFollow
+	search.MaxDepth = option.MaxDepth
+	search.MaxSymlinkChainLength = option.MaxSymlinkChainLength
+	search.MaxDirs = option.MaxDirs
+	search.Parallel = option.Parallel
+	search.WorkingDir = option.WorkingDir
+	search.AllowedDirs = option.AllowedDirs
+	search.WalkErrHandler = option.WalkErrHandler
+	search.FollowSymlinks = option.FollowSymlinks
+	search.SkipHidden = option.SkipHidden
+	search.Sort = option.Sort
+	search.BreadthFirst = option.BreadthFirst
+	search.SkipMountPoints = option.SkipMountPoints
+	search.Expr = expr.Glob
+	search.IgnoreCase = true
+	res, err := wh.MatchResults(search, "*.zip", sub...)
+	archive := make([]string, len(res))
+	for i, r := range res {
+		archive[i] = r.Chain.Tail().Path()
+	}
+	return archive, err
+}
+
+// matchArchives returns a Result for each file in each of the given ZIP
+// archives whose name matches the given string pattern, and whose content
+// matches option.ContentPattern if set.
+// The first error encountered reading any archive is returned after all
+// archives have been searched.
+func matchArchives(option wh.Option, pattern string, archive []string) ([]wh.Result, error) {
+	var found []wh.Result
+	var first error
+	for _, a := range archive {
+		f, err := wh.MatchZipResults(option, pattern, a)
+		if err != nil && first == nil {
+			first = err
+		}
+		found = append(found, f...)
+	}
+	return found, first
+}
+
+// isArchived reports whether the given Result r is a file found in any of the
+// given ZIP archives.
+func isArchived(r wh.Result, archive []string) bool {
+	for _, a := range archive {
+		if r.Root == a {
+			return true
+		}
+	}
+	return false
+}
+
+// formatResult writes the output of executing the given text/template tmpl with
+// the attributes of the file described by the given Result r to the given
+// io.Writer w. The attributes of files found in any of the given ZIP archives
+// are read from the archive rather than the file system.
+func formatResult(w io.Writer, tmpl string, r wh.Result, archive []string) error {
+	if !isArchived(r, archive) {
+		return wh.FormatResult(w, tmpl, r.Chain.Head().Path())
+	}
+	info, err := r.Info()
+	if err != nil {
+		return err
+	}
+	return wh.FormatFileInfo(w, tmpl, r.Path, info)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ardnew/wh"
+)
+
+func TestArchives(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "arc.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	fw, err := w.Create("inner.txt")
+	if err == nil {
+		_, err = fw.Write([]byte("inner"))
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The filters applied to matching files are not applied to the archives.
+	option := wh.DefaultOption()
+	option.ExecutableOnly = true
+	option.MinSize = 1 << 20
+	option.Exclude = []string{"*.zip"}
+	archives, err := findArchives(option, dir)
+	if err != nil || len(archives) != 1 || archives[0] != archive {
+		t.Fatalf("findArchives() = %q, %v", archives, err)
+	}
+
+	res, err := matchArchives(wh.DefaultOption(), "inner.txt", archives)
+	if err != nil || len(res) != 1 {
+		t.Fatalf("matchArchives() = %v, %v", res, err)
+	}
+	var sb strings.Builder
+	if err := formatResult(&sb, "{{.Path}} {{.Size}}", res[0], archives); err != nil {
+		t.Fatal(err)
+	}
+	if want := archive + "/inner.txt 5"; sb.String() != want {
+		t.Fatalf("formatResult() wrote %q, want %q", sb.String(), want)
+	}
+}
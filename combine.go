@@ -0,0 +1,73 @@
+package wh
+
+import "sync"
+
+// And returns a MatchFunc that reports each file reported by every one of the
+// given MatchFuncs fns, called with the same arguments, in the order reported
+// by the first. Files are compared as with PathList.Intersect.
+//
+// The error of the first MatchFunc in fns returning an error, if any, is
+// returned along with the files reported by every MatchFunc.
+func And(fns ...MatchFunc) MatchFunc {
+	return combine(fns, PathList.Intersect)
+}
+
+// Or returns a MatchFunc that reports each file reported by any of the given
+// MatchFuncs fns, called with the same arguments, in the order reported by each
+// MatchFunc in fns, omitting duplicates. Files are compared as with
+// PathList.Union.
+//
+// The error of the first MatchFunc in fns returning an error, if any, is
+// returned along with the files reported by any MatchFunc.
+func Or(fns ...MatchFunc) MatchFunc {
+	return combine(fns, PathList.Union)
+}
+
+// combine returns a MatchFunc that reports the files of each of the given
+// MatchFuncs fns combined in order by the given function op. The MatchFuncs are
+// called concurrently if option.Parallel is greater than 1.
+//
+// Each MatchFunc must complete its search to combine the files reported, so
+// the result is limited to option.MaxResults files only after combining.
+func combine(fns []MatchFunc, op func(PathList, PathList) PathList) MatchFunc {
+	return func(option Option, pattern string, sub ...string) ([]string, error) {
+		if len(fns) == 0 {
+			return nil, nil
+		}
+		limit := option.MaxResults
+		option.MaxResults = 0
+		found := make([]PathList, len(fns))
+		err := make([]error, len(fns))
+		call := func(i int) {
+			found[i], err[i] = fns[i](option, pattern, sub...)
+		}
+		if option.Parallel > 1 {
+			var wg sync.WaitGroup
+			for i := range fns {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					call(i)
+				}(i)
+			}
+			wg.Wait()
+		} else {
+			for i := range fns {
+				call(i)
+			}
+		}
+		all := found[0]
+		for _, f := range found[1:] {
+			all = op(all, f)
+		}
+		if limit > 0 && len(all) > limit {
+			all = all[:limit]
+		}
+		for _, e := range err {
+			if e != nil {
+				return all, e
+			}
+		}
+		return all, nil
+	}
+}
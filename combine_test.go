@@ -0,0 +1,35 @@
+package wh
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCombine(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "", "b.go": "", "[ab].go": ""})
+	for _, parallel := range []int{0, 2} {
+		option := DefaultOption()
+		option.Parallel = parallel
+
+		found, err := And(MatchFixed, MatchGlob)(option, "a.go", dir)
+		expect(t, found, err, join(dir, "a.go")...)
+		found, err = And(MatchFixed, MatchGlob)(option, "[ab].go", dir)
+		expect(t, found, err)
+		found, err = Or(MatchFixed, MatchGlob)(option, "[ab].go", dir)
+		expect(t, found, err, join(dir, "[ab].go", "a.go", "b.go")...)
+		found, err = Or(MatchGlob, MatchGlob)(option, "[ab].go", dir)
+		expect(t, found, err, join(dir, "a.go", "b.go")...)
+		found, err = And()(option, "a.go", dir)
+		expect(t, found, err)
+
+		errFail := errors.New("fail")
+		fail := func(Option, string, ...string) ([]string, error) { return nil, errFail }
+		if _, err := And(MatchFixed, fail)(option, "a.go", dir); !errors.Is(err, errFail) {
+			t.Fatalf("And() error = %v, want %v", err, errFail)
+		}
+		found, err = Or(fail, MatchFixed)(option, "a.go", dir)
+		if !errors.Is(err, errFail) || len(found) != 1 {
+			t.Fatalf("Or() = %q, %v, want 1 file and %v", found, err, errFail)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package wh
+
+import (
+	"path"
+	"regexp"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// MatchCompiled is a pattern that has been prepared for matching file names,
+// according to the Option with which it was compiled, across multiple searches
+// without repeating any setup.
+type MatchCompiled struct {
+	option  Option
+	pattern string
+}
+
+// Compile prepares the given string pattern for matching file names according
+// to the semantics of option.Expr, returning an error if the pattern is invalid
+// for option.Expr or if option is invalid.
+//
+// The pattern is folded according to option.IgnoreCase, and regular expression
+// patterns are compiled, only once for all subsequent calls to Find.
+func Compile(option Option, pattern string) (*MatchCompiled, error) {
+	if err := option.Validate(); err != nil {
+		return nil, err
+	}
+	c := &MatchCompiled{option: option, pattern: option.fold(pattern)}
+	switch option.Expr {
+	case expr.Fixed, expr.Fuzzy, expr.Suffix, expr.BraceExpr:
+	case expr.Glob:
+		if _, err := path.Match(c.pattern, ""); err != nil {
+			return nil, err
+		}
+	case expr.Regexp:
+		re, err := regexp.Compile(c.pattern)
+		if err != nil {
+			return nil, err
+		}
+		c.option.matcher = func(_, name string) (bool, error) {
+			return re.MatchString(name), nil
+		}
+	default:
+		return nil, expr.ErrInvalidExpr(option.Expr)
+	}
+	return c, nil
+}
+
+// Find returns a list of all files found in each of the given directories dirs
+// whose name matches the receiver's compiled pattern.
+func (c *MatchCompiled) Find(dirs ...string) ([]string, error) {
+	return Match(c.option, c.pattern, dirs...)
+}
@@ -0,0 +1,43 @@
+package wh
+
+import (
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestCompile(t *testing.T) {
+	dir := makeTree(t, map[string]string{"x/Main.go": "", "y/main.go": "", "y/util.go": ""})
+	x, y := join(dir, "x")[0], join(dir, "y")[0]
+	for _, tc := range []struct {
+		e       expr.Expr
+		pattern string
+		want    []string
+	}{
+		{expr.Fixed, "MAIN.GO", join(dir, "x/Main.go", "y/main.go")},
+		{expr.Glob, "m*", join(dir, "x/Main.go", "y/main.go")},
+		{expr.Regexp, `^u`, join(dir, "y/util.go")},
+		{expr.Suffix, "IL.GO", join(dir, "y/util.go")},
+	} {
+		option := NewOption(WithExpr(tc.e), WithIgnoreCase(true))
+		c, err := Compile(option, tc.pattern)
+		if err != nil {
+			t.Fatalf("Compile(%v, %q) error: %v", tc.e, tc.pattern, err)
+		}
+		found, err := c.Find(x, y)
+		expect(t, found, err, tc.want...)
+	}
+	for _, tc := range []struct {
+		option  Option
+		pattern string
+	}{
+		{NewOption(WithExpr(expr.Glob)), "["},
+		{NewOption(WithExpr(expr.Regexp)), "("},
+		{NewOption(WithExpr(expr.Expr(99))), "x"},
+		{Option{SkipHidden: true, HiddenOnly: true}, "x"},
+	} {
+		if _, err := Compile(tc.option, tc.pattern); err == nil {
+			t.Errorf("Compile(%v, %q) = nil error", tc.option.Expr, tc.pattern)
+		}
+	}
+}
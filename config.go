@@ -0,0 +1,46 @@
+package wh
+
+//go:generate go run ./schema/gen.go
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// jsonOption has the same fields as Option but none of its methods, so that it
+// can be encoded and decoded by the default behavior of package json.
+type jsonOption Option
+
+// MarshalJSON implements the json.Marshaler interface's MarshalJSON method.
+// Each exported field of the receiver Option option is encoded by name, with
+// Expr encoded as its string representation (e.g., "glob"). Function-valued
+// fields and Stats are omitted.
+func (option Option) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonOption(option))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface's UnmarshalJSON
+// method. Fields of the receiver *Option option that are not present in data
+// retain their current value.
+func (option *Option) UnmarshalJSON(data []byte) error {
+	o := jsonOption(*option)
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+	*option = Option(o)
+	return nil
+}
+
+// LoadConfig returns the Option decoded from the JSON file at the given path.
+// Fields not present in the file have the value given by DefaultOption.
+func LoadConfig(path string) (Option, error) {
+	option := DefaultOption()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return option, err
+	}
+	if err := json.Unmarshal(data, &option); err != nil {
+		return option, err
+	}
+	return option, nil
+}
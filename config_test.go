@@ -0,0 +1,91 @@
+package wh
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// exportedOption returns an Option with every exported field that is encoded
+// as JSON set to a value other than its zero value or default.
+func exportedOption(t *testing.T) Option {
+	t.Helper()
+	var option Option
+	v := reflect.ValueOf(&option).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f, sf := v.Field(i), v.Type().Field(i)
+		if !sf.IsExported() || sf.Tag.Get("json") == "-" {
+			continue
+		}
+		switch f.Interface().(type) {
+		case expr.Expr:
+			f.Set(reflect.ValueOf(expr.BraceExpr))
+		case time.Time:
+			f.Set(reflect.ValueOf(time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC).Add(time.Duration(i))))
+		case []string:
+			f.Set(reflect.ValueOf([]string{sf.Name, "b"}))
+		default:
+			switch f.Kind() {
+			case reflect.Bool:
+				f.SetBool(true)
+			case reflect.String:
+				f.SetString(sf.Name)
+			case reflect.Int, reflect.Int64:
+				f.SetInt(int64(i + 2))
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+				f.SetUint(uint64(i + 2))
+			default:
+				t.Fatalf("unhandled field %s of type %s", sf.Name, sf.Type)
+			}
+		}
+	}
+	return option
+}
+
+func TestOptionJSON(t *testing.T) {
+	want := exportedOption(t)
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"Expr":"brace"`) {
+		t.Errorf("Expr not encoded by name: %s", data)
+	}
+	for _, internal := range []string{"fromDepth", "fromFollow", "TraceHook", "Stats"} {
+		if strings.Contains(string(data), `"`+internal+`"`) {
+			t.Errorf("%s encoded: %s", internal, data)
+		}
+	}
+	var got Option
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(name, []byte(`{"Expr":"glob","MaxDepth":3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	option, err := LoadConfig(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultOption()
+	want.Expr, want.MaxDepth = expr.Glob, 3
+	if !reflect.DeepEqual(option, want) {
+		t.Fatalf("LoadConfig() = %+v, want %+v", option, want)
+	}
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); !os.IsNotExist(err) {
+		t.Fatalf("LoadConfig(missing) error = %v", err)
+	}
+}
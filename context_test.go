@@ -0,0 +1,55 @@
+//go:build unix
+
+package wh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestContextSymlinkCancel(t *testing.T) {
+	file := map[string]string{}
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			file[fmt.Sprintf("real/d%d/f%d", i, j)] = ""
+		}
+	}
+	file["root/"] = ""
+	dir := makeTree(t, file)
+	if err := os.Symlink(filepath.Join(dir, "real"), filepath.Join(dir, "root", "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var before, after int
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 10
+	option.MaxFollow = 10
+	option.FollowSymlinks = true
+	option.TraceHook = func(e TraceEvent) {
+		if ctx.Err() != nil {
+			after++
+			return
+		}
+		before++
+		if strings.Contains(e.Path, "d0") {
+			cancel() // Cancel the walk within the symlinked directory.
+		}
+	}
+	_, err := MatchWithContext(ctx, option, "*", filepath.Join(dir, "root"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("MatchWithContext() error = %v, want context.Canceled", err)
+	}
+	if before == 0 || after > 2 {
+		t.Fatalf("walk steps before cancel = %d, after = %d (want at most 2)", before, after)
+	}
+}
@@ -0,0 +1,36 @@
+package wh
+
+import (
+	"context"
+	"path/filepath"
+)
+
+// MatchDedupe returns the result of calling Match with option.Unique, so that
+// only the first file found that resolves to each canonical path is reported.
+func MatchDedupe(option Option, pattern string, sub ...string) ([]string, error) {
+	option.Unique = true
+	return MatchWithContext(context.Background(), option, option.fold(pattern), sub...)
+}
+
+// matchUnique calls match with a visitFunc that calls the given visitFunc visit
+// only for the first file found that resolves to each canonical path. The path
+// of each file visited is not modified.
+//
+// The canonical path of each file is determined by filepath.EvalSymlinks, so
+// that multiple symlinks referring to the same file are reported only once.
+// Files whose canonical path cannot be determined are compared verbatim.
+func matchUnique(ctx context.Context, option Option, pattern string, sub []string, visit visitFunc) error {
+	option.Unique = false
+	seen := map[string]struct{}{}
+	return match(ctx, option, pattern, sub, func(r Result) error {
+		p := r.Chain.Head().Path()
+		if real, err := filepath.EvalSymlinks(p); err == nil {
+			p = real
+		}
+		if _, ok := seen[p]; ok {
+			return nil
+		}
+		seen[p] = struct{}{}
+		return visit(r)
+	})
+}
@@ -0,0 +1,37 @@
+//go:build unix
+
+package wh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestMatchDedupe(t *testing.T) {
+	dir := makeTree(t, map[string]string{"real.txt": "", "sub/": ""})
+	real := filepath.Join(dir, "real.txt")
+	if err := os.Link(real, filepath.Join(dir, "hard.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, filepath.Join(dir, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, filepath.Join(dir, "sub", "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 2
+	option.SymlinksAsFiles = true
+
+	found, err := Match(option, "*.txt", dir)
+	expect(t, found, err, join(dir, "hard.txt", "link.txt", "real.txt", "sub/link.txt")...)
+
+	// Hard links are distinct files by canonical path, but each symlink is
+	// reported by its own path only if it is the first found for its target.
+	found, err = MatchDedupe(option, "*.txt", dir)
+	expect(t, found, err, join(dir, "hard.txt", "link.txt")...)
+}
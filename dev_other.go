@@ -0,0 +1,35 @@
+//go:build !unix
+
+package wh
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// device returns the ID of the device containing the file described by info,
+// and whether or not the ID could be determined, which it cannot on this
+// platform.
+func device(info fs.FileInfo) (uint64, bool) { return 0, false }
+
+// nlink returns the number of hard links to the file described by info, and
+// whether or not the number could be determined, which it cannot on this
+// platform. The Win32 API only reports the number of links of an open file
+// handle (GetFileInformationByHandle), which fs.FileInfo does not provide.
+func nlink(info fs.FileInfo) (uint64, bool) { return 0, false }
+
+// inode returns the inode number of the file described by info, and whether or
+// not the number could be determined, which it cannot on this platform.
+func inode(info fs.FileInfo) (uint64, bool) { return 0, false }
+
+// identify returns the fileID of the file at path p described by info, and
+// whether or not it could be determined. Device IDs and inode numbers are not
+// reported on this platform, so the file is instead identified by its path
+// after resolving all symlinks.
+func identify(p string, info fs.FileInfo) (fileID, bool) {
+	real, err := filepath.EvalSymlinks(filepath.FromSlash(p))
+	if err != nil {
+		return fileID{}, false
+	}
+	return fileID{path: real}, true
+}
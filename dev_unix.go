@@ -0,0 +1,44 @@
+//go:build unix
+
+package wh
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// device returns the ID of the device containing the file described by info,
+// and whether or not the ID could be determined.
+func device(info fs.FileInfo) (uint64, bool) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Dev), true
+	}
+	return 0, false
+}
+
+// nlink returns the number of hard links to the file described by info, and
+// whether or not the number could be determined.
+func nlink(info fs.FileInfo) (uint64, bool) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Nlink), true
+	}
+	return 0, false
+}
+
+// inode returns the inode number of the file described by info, and whether or
+// not the number could be determined.
+func inode(info fs.FileInfo) (uint64, bool) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino), true
+	}
+	return 0, false
+}
+
+// identify returns the fileID of the file at path p described by info, which is
+// its device ID and inode number, and whether or not it could be determined.
+func identify(p string, info fs.FileInfo) (fileID, bool) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+	}
+	return fileID{}, false
+}
@@ -0,0 +1,86 @@
+package wh
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+)
+
+// hashBufferSize is the size of the buffer used to stream file content into
+// a hash, so that large files are never read entirely into memory.
+const hashBufferSize = 32 * 1024
+
+// MatchDuplicates returns each group of files matching the given string pattern
+// according to option.Expr whose content is identical, as determined by the
+// SHA-256 hash of each file. Each group contains at least two files, and the
+// groups are ordered by the first file found in each.
+//
+// Only files of equal size are hashed, so that files with unique sizes are
+// never read. Files that cannot be read are excluded from all groups.
+//
+// If option.ContentHash is false, no file is read, and files are grouped by
+// size alone. Such groups are only candidate duplicates.
+func MatchDuplicates(option Option, pattern string, sub ...string) ([][]string, error) {
+	type sized struct {
+		Result
+		size int64
+	}
+	var found []sized
+	count := map[int64]int{}
+	err := match(context.Background(), option, option.fold(pattern), sub, func(r Result) error {
+		// Stat the file referred to by each symlink, not the symlink itself.
+		if info, ierr := r.Chain.Tail().Stat(); ierr == nil {
+			count[info.Size()]++
+			found = append(found, sized{Result: r, size: info.Size()})
+		}
+		return nil
+	})
+	var group [][]string
+	index := map[[sha256.Size]byte]int{}
+	buf := make([]byte, hashBufferSize)
+	for _, r := range found {
+		if count[r.size] < 2 {
+			continue
+		}
+		var sum [sha256.Size]byte
+		if option.ContentHash {
+			var herr error
+			if sum, herr = hashFile(r.Chain.Tail(), buf); herr != nil {
+				continue
+			}
+		} else {
+			// Distinguish groups by size alone.
+			binary.LittleEndian.PutUint64(sum[:], uint64(r.size))
+		}
+		if i, ok := index[sum]; ok {
+			group[i] = append(group[i], r.String())
+		} else {
+			index[sum] = len(group)
+			group = append(group, []string{r.String()})
+		}
+	}
+	dupe := group[:0]
+	for _, g := range group {
+		if len(g) > 1 {
+			dupe = append(dupe, g)
+		}
+	}
+	return dupe, err
+}
+
+// hashFile returns the SHA-256 hash of the content of the file referred to by
+// the given Link l, read in chunks using the given buffer buf.
+func hashFile(l *Link, buf []byte) (sum [sha256.Size]byte, err error) {
+	f, err := l.Open()
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.CopyBuffer(h, f, buf); err != nil {
+		return
+	}
+	copy(sum[:], h.Sum(nil))
+	return
+}
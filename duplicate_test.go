@@ -0,0 +1,40 @@
+package wh
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestMatchDuplicates(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"a.txt": "same",
+		"b.txt": "same",
+		"c.txt": "diff",
+		"d.txt": "unique size",
+		"e.txt": "diff",
+		"f.txt": "othr",
+	})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	group, err := MatchDuplicates(option, "*.txt", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{join(dir, "a.txt", "b.txt"), join(dir, "c.txt", "e.txt")}
+	if !reflect.DeepEqual(group, want) {
+		t.Fatalf("MatchDuplicates() = %q, want %q", group, want)
+	}
+
+	// Without ContentHash, files of equal size are grouped without being read.
+	option.ContentHash = false
+	group, err = MatchDuplicates(option, "*.txt", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = [][]string{join(dir, "a.txt", "b.txt", "c.txt", "e.txt", "f.txt")}
+	if !reflect.DeepEqual(group, want) {
+		t.Fatalf("MatchDuplicates() without ContentHash = %q, want %q", group, want)
+	}
+}
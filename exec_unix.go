@@ -0,0 +1,11 @@
+//go:build !windows
+
+package wh
+
+import "io/fs"
+
+// isExecutable reports whether the file described by info has any of its
+// execute permission bits set.
+func isExecutable(info fs.FileInfo) bool {
+	return info.Mode()&0o111 != 0
+}
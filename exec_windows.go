@@ -0,0 +1,13 @@
+//go:build windows
+
+package wh
+
+import "io/fs"
+
+// isExecutable reports whether the file described by info has any of its
+// execute permission bits set or, since Windows has no such bits, whether its
+// extension is listed in the PATHEXT environment variable (by default, .com,
+// .exe, .bat, and .cmd).
+func isExecutable(info fs.FileInfo) bool {
+	return info.Mode()&0o111 != 0 || executableExt(info.Name())
+}
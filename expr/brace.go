@@ -0,0 +1,93 @@
+package expr
+
+import "strings"
+
+// BraceExpand returns each string produced by the shell brace expansion of the
+// given pattern, in order. For example, "{a,b}{c,d}" expands to "ac", "ad",
+// "bc", and "bd", and "x{a,{b,c}}" expands to "xa", "xb", and "xc".
+//
+// As with the shell, a pair of braces is expanded only if it contains at least
+// one comma that is not nested in another pair of braces; otherwise, including
+// "{}" and "{a}", the braces are copied literally. A brace or comma preceded by
+// a backslash is also copied literally, and each such backslash is removed from
+// the expanded strings.
+func BraceExpand(pattern string) []string {
+	raw := braceExpand(pattern)
+	for i, s := range raw {
+		raw[i] = unescape(s)
+	}
+	return raw
+}
+
+// braceExpand returns the brace expansion of the given pattern, retaining any
+// backslash escapes.
+func braceExpand(pattern string) []string {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++ // Skip the escaped byte.
+		case '{':
+			alt, end := braceAlt(pattern[i:])
+			if alt == nil {
+				continue // Copy literally, but expand any nested braces.
+			}
+			prefix, suffix := pattern[:i], braceExpand(pattern[i+end:])
+			var exp []string
+			for _, a := range alt {
+				for _, x := range braceExpand(a) {
+					for _, y := range suffix {
+						exp = append(exp, prefix+x+y)
+					}
+				}
+			}
+			return exp
+		}
+	}
+	return []string{pattern}
+}
+
+// braceAlt returns each comma-separated alternative in the brace expression at
+// the beginning of the given string s, along with the index following its
+// closing brace. If s does not begin with a brace expression containing at
+// least one comma, braceAlt returns nil and 0.
+func braceAlt(s string) ([]string, int) {
+	var alt []string
+	depth, start := 0, 1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alt = append(alt, s[start:i])
+				start = i + 1
+			}
+		case '}':
+			if depth--; depth == 0 {
+				if alt == nil {
+					return nil, 0
+				}
+				return append(alt, s[start:i]), i + 1
+			}
+		}
+	}
+	return nil, 0
+}
+
+// unescape returns the given string s with each backslash removed that escapes
+// the byte following it.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package expr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBraceExpand(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		want    []string
+	}{
+		{"", []string{""}},
+		{"{}", []string{"{}"}},         // Empty braces
+		{"a{}b", []string{"a{}b"}},     // Empty braces
+		{"{a}", []string{"{a}"}},       // Single element
+		{"x{a}y", []string{"x{a}y"}},   // Single element
+		{"{,x}y", []string{"y", "xy"}}, // Empty element
+		{"{a,b}{c,d}", []string{"ac", "ad", "bc", "bd"}},
+		{"x{a,{b,c}}", []string{"xa", "xb", "xc"}}, // Nested braces
+		{"{a,{b}}", []string{"a", "{b}"}},          // Nested single element
+		{`\{a,b\}`, []string{"{a,b}"}},             // Escaped braces
+		{`{a\,b,c}`, []string{"a,b", "c"}},         // Escaped comma
+		{"{a,b", []string{"{a,b"}},                 // Unbalanced
+	} {
+		if got := BraceExpand(tt.pattern); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BraceExpand(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestBraceExprText(t *testing.T) {
+	if BraceExpr != 9 {
+		t.Fatalf("BraceExpr = %d, want 9", BraceExpr)
+	}
+	b, err := BraceExpr.MarshalText()
+	if err != nil || string(b) != "brace" {
+		t.Fatalf("MarshalText() = %q, %v", b, err)
+	}
+	var e Expr
+	if err := e.UnmarshalText(b); err != nil || e != BraceExpr {
+		t.Fatalf("UnmarshalText(%q) = %v, %v", b, e, err)
+	}
+	for _, x := range []Expr{5, 8, 10, -1} {
+		if _, err := x.MarshalText(); err == nil {
+			t.Errorf("MarshalText(%d) = nil error", x)
+		}
+	}
+	if ok, err := BraceExpr.Match("{a,b}.txt", "b.txt"); !ok || err != nil {
+		t.Fatalf("Match() = %t, %v", ok, err)
+	}
+}
@@ -1,8 +1,11 @@
 package expr
 
 import (
+	"container/list"
 	"regexp"
+	"runtime"
 	"sync"
+	"sync/atomic"
 )
 
 // Cache defines a memoized data structure that associates regular expression
@@ -12,27 +15,224 @@ import (
 //
 // From a (Expr).Match context, it enables reuse of regexp.Regexp objects across
 // multiple calls without having to recompile the pattern string each time.
+//
+// If MaxSize is greater than 0, the least recently used pattern is evicted
+// whenever adding a pattern would exceed MaxSize patterns. MaxSize must not be
+// modified concurrently with any method of Cache.
 type Cache struct {
 	*sync.RWMutex
-	re map[string]*regexp.Regexp
+	MaxSize int                      // Maximum number of patterns (0 = unlimited)
+	re      map[string]*list.Element // Element of lru containing each pattern
+	lru     *list.List               // Each cacheEntry, most recently used first
+
+	hits, misses, evictions atomic.Uint64
+}
+
+// CacheStats contains statistics describing the use of a Cache.
+type CacheStats struct {
+	Hits      uint64 // Number of calls to Get with a pattern already compiled
+	Misses    uint64 // Number of calls to Get with a pattern not yet compiled
+	Evictions uint64 // Number of patterns evicted to satisfy MaxSize
+	Size      uint64 // Number of patterns currently compiled
+}
+
+// cacheEntry associates a pattern with its compiled regexp.Regexp in the LRU
+// list of a Cache.
+type cacheEntry struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// NewCache returns a new empty Cache retaining at most maxSize patterns
+// (0 = unlimited).
+func NewCache(maxSize int) *Cache {
+	return &Cache{
+		RWMutex: &sync.RWMutex{},
+		MaxSize: maxSize,
+		re:      map[string]*list.Element{},
+		lru:     list.New(),
+	}
 }
 
 // Get returns a compiled regexp.Regexp object for the given regular expression
 // string pattern. The pattern will be compiled and added to the receiver Cache
-// if it is not present. This method is safe to call from multiple goroutines
-// concurrently.
+// if it is not present, or otherwise marked as the most recently used. This
+// method is safe to call from multiple goroutines concurrently.
 func (c *Cache) Get(pattern string) (*regexp.Regexp, error) {
+	lookup := func() (re *regexp.Regexp, ok bool) {
+		if e, ok := c.re[pattern]; ok {
+			return e.Value.(*cacheEntry).re, true
+		}
+		return nil, false
+	}
 	c.RLock()
-	r, ok := c.re[pattern]
+	re, ok := lookup()
+	lru := ok && c.MaxSize > 0
 	c.RUnlock()
-	if !ok {
-		var err error
-		if r, err = regexp.Compile(pattern); err != nil {
-			return nil, err
-		}
+	if lru {
+		// Marking the pattern as used modifies the LRU list, requiring a write
+		// lock, which is needed only if patterns may be evicted.
 		c.Lock()
-		c.re[pattern] = r
+		if re, ok = lookup(); ok {
+			c.lru.MoveToFront(c.re[pattern])
+		}
 		c.Unlock()
 	}
+	if ok {
+		c.hits.Add(1)
+		return re, nil
+	}
+	c.misses.Add(1)
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	c.Lock()
+	c.put(pattern, r)
+	c.Unlock()
 	return r, nil
 }
+
+// put adds the given pattern and its compiled regexp.Regexp r to the receiver
+// Cache as the most recently used, evicting the least recently used patterns
+// in excess of c.MaxSize. The caller must hold the write lock.
+func (c *Cache) put(pattern string, r *regexp.Regexp) {
+	if e, ok := c.re[pattern]; ok {
+		e.Value.(*cacheEntry).re = r
+		c.lru.MoveToFront(e)
+	} else {
+		c.re[pattern] = c.lru.PushFront(&cacheEntry{pattern: pattern, re: r})
+	}
+	c.evict()
+}
+
+// evict removes the least recently used patterns from the receiver Cache until
+// it contains no more than c.MaxSize patterns, if c.MaxSize is greater than 0.
+// The caller must hold the write lock.
+func (c *Cache) evict() {
+	for c.MaxSize > 0 && c.lru.Len() > c.MaxSize {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.re, e.Value.(*cacheEntry).pattern)
+		c.evictions.Add(1)
+	}
+}
+
+// Stats returns the statistics of the receiver Cache accumulated since it was
+// created or ResetStats was last called.
+// This method is safe to call from multiple goroutines concurrently.
+func (c *Cache) Stats() CacheStats {
+	c.RLock()
+	size := len(c.re)
+	c.RUnlock()
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      uint64(size),
+	}
+}
+
+// ResetStats sets the hit, miss, and eviction counters of the receiver Cache to
+// zero. The compiled patterns are not modified.
+// This method is safe to call from multiple goroutines concurrently.
+func (c *Cache) ResetStats() {
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.evictions.Store(0)
+}
+
+// Delete removes the compiled regexp.Regexp object for the given regular
+// expression string pattern from the receiver Cache, if present, so that it
+// will be compiled again by the next call to Get.
+// This method is safe to call from multiple goroutines concurrently.
+func (c *Cache) Delete(pattern string) {
+	c.Lock()
+	if e, ok := c.re[pattern]; ok {
+		c.lru.Remove(e)
+		delete(c.re, pattern)
+	}
+	c.Unlock()
+}
+
+// DeleteAll removes all compiled regexp.Regexp objects from the receiver Cache.
+// This method is safe to call from multiple goroutines concurrently.
+func (c *Cache) DeleteAll() {
+	c.Lock()
+	clear(c.re)
+	c.lru.Init()
+	c.Unlock()
+}
+
+// Precompile compiles each of the given regular expression patterns and adds
+// those that compile successfully to the receiver Cache, so that an invalid
+// pattern is detected before any of the patterns are used, and the first call
+// to Get with each valid pattern is never a miss. Every pattern is compiled
+// regardless of errors, and the returned slice contains the error of each
+// pattern at the same index (nil for each pattern compiled successfully).
+// This method is safe to call from multiple goroutines concurrently.
+func (c *Cache) Precompile(pattern []string) []error {
+	re, err := c.compile(pattern)
+	c.add(pattern, re)
+	return err
+}
+
+// parallelCompile is the minimum number of patterns compiled concurrently by
+// the compile method of Cache.
+const parallelCompile = 11
+
+// compile returns the compiled regexp.Regexp and error of each of the given
+// patterns at the same index, reusing any already present in the receiver
+// Cache. Patterns are compiled concurrently if there are at least
+// parallelCompile of them.
+func (c *Cache) compile(pattern []string) ([]*regexp.Regexp, []error) {
+	re := make([]*regexp.Regexp, len(pattern))
+	err := make([]error, len(pattern))
+	c.RLock()
+	for i, p := range pattern {
+		if e, ok := c.re[p]; ok {
+			re[i] = e.Value.(*cacheEntry).re
+		}
+	}
+	c.RUnlock()
+	one := func(i int) {
+		if re[i] == nil {
+			re[i], err[i] = regexp.Compile(pattern[i])
+		}
+	}
+	if len(pattern) < parallelCompile {
+		for i := range pattern {
+			one(i)
+		}
+		return re, err
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for n := min(runtime.GOMAXPROCS(0), len(pattern)); n > 0; n-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				one(i)
+			}
+		}()
+	}
+	for i := range pattern {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return re, err
+}
+
+// add adds each non-nil compiled regexp.Regexp in re to the receiver Cache,
+// keyed by the pattern at the same index, as the most recently used.
+func (c *Cache) add(pattern []string, re []*regexp.Regexp) {
+	c.Lock()
+	for i, r := range re {
+		if r != nil {
+			c.put(pattern[i], r)
+		}
+	}
+	c.Unlock()
+}
@@ -0,0 +1,104 @@
+package expr
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestCacheLRU(t *testing.T) {
+	c := NewCache(2)
+	for _, p := range []string{"a", "b", "a", "c"} {
+		if _, err := c.Get(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// "b" is the least recently used, since "a" was used after it.
+	c.RLock()
+	_, a := c.re["a"]
+	_, b := c.re["b"]
+	c.RUnlock()
+	if !a || b {
+		t.Fatalf("cached a = %t, b = %t, want true, false", a, b)
+	}
+	if s := c.Stats(); s.Evictions != 1 || s.Size != 2 {
+		t.Fatalf("Stats() = %+v", s)
+	}
+}
+
+func TestCacheConcurrentGet(t *testing.T) {
+	for _, size := range []int{0, 2} {
+		c := NewCache(size)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for _, p := range []string{"a+", "b+", "c+"} {
+					re, err := c.Get(p)
+					if err != nil || re.String() != p {
+						t.Errorf("Get(%q) = %v, %v", p, re, err)
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+		if s := c.Stats(); s.Hits+s.Misses != 24 {
+			t.Fatalf("MaxSize %d: Stats() = %+v", size, s)
+		}
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	c := NewCache(1)
+	for _, p := range []string{"a", "a", "b", "a", "("} {
+		_, _ = c.Get(p)
+	}
+	// "b" evicts "a", which evicts "b"; the invalid "(" is a miss.
+	want := CacheStats{Hits: 1, Misses: 4, Evictions: 2, Size: 1}
+	if s := c.Stats(); s != want {
+		t.Fatalf("Stats() = %+v, want %+v", s, want)
+	}
+	c.ResetStats()
+	if s := c.Stats(); s != (CacheStats{Size: 1}) {
+		t.Fatalf("Stats() after ResetStats = %+v, want only Size 1", s)
+	}
+}
+
+func TestCachePrecompile(t *testing.T) {
+	c := NewCache(0)
+	err := c.Precompile([]string{"a+", "(", "b+"})
+	if len(err) != 3 || err[0] != nil || err[1] == nil || err[2] != nil {
+		t.Fatalf("Precompile([a+ ( b+]) = %v", err)
+	}
+	if s := c.Stats(); s.Size != 2 {
+		t.Fatalf("Precompile() added %d patterns, want 2", s.Size)
+	}
+	// Compiled concurrently, reusing "a+" already in the cache.
+	var pattern []string
+	for i := 0; i < 2*parallelCompile; i++ {
+		pattern = append(pattern, "x{"+strconv.Itoa(i)+"}")
+	}
+	pattern[3] = "("
+	err = c.Precompile(append(pattern, "a+"))
+	for i, e := range err {
+		if (e != nil) != (i == 3) {
+			t.Errorf("Precompile()[%d] = %v", i, e)
+		}
+	}
+	if s := c.Stats(); s.Size != uint64(len(pattern)+1) {
+		t.Fatalf("Stats().Size = %d, want %d", s.Size, len(pattern)+1)
+	}
+	c.Delete("a+")
+	c.Delete("missing")
+	if _, err := c.Get("b+"); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.Stats(); s.Size != uint64(len(pattern)) || s.Hits != 1 || s.Misses != 0 {
+		t.Fatalf("Stats() = %+v after Delete", s)
+	}
+	c.DeleteAll()
+	if s := c.Stats(); s.Size != 0 {
+		t.Fatalf("Stats().Size = %d after DeleteAll", s.Size)
+	}
+}
@@ -5,14 +5,17 @@ import (
 	"path"
 	"regexp"
 	"strconv"
-	"sync"
+	"strings"
+
+	"github.com/ardnew/wh/fuzzy"
 )
 
 // Error types specific to package expr that may be returned by one of its
 // exported functions or methods. Use type assertion to determine the type of
 // error and the interface func Error() for a descriptive error message.
 type (
-	ErrInvalidExpr Expr
+	ErrInvalidExpr     Expr
+	ErrInvalidExprName string
 )
 
 // Error returns a descriptive error string for the receiver ErrInvalidExpr e.
@@ -20,6 +23,12 @@ func (e ErrInvalidExpr) Error() string {
 	return "invalid Expr: int(" + strconv.Itoa(int(e)) + ")"
 }
 
+// Error returns a descriptive error string for the receiver ErrInvalidExprName
+// e.
+func (e ErrInvalidExprName) Error() string {
+	return "invalid Expr: " + strconv.Quote(string(e))
+}
+
 // Expr enumerates all supported types of match expressions.
 type Expr int
 
@@ -28,19 +37,72 @@ const (
 	Fixed  Expr = iota // Match entire file names verbatim
 	Glob               // Match using standard Go path.Match semantics
 	Regexp             // Match using standard Go regexp.Regexp semantics
-	numExpr
+	Fuzzy              // Match file names within an edit distance of the pattern
+	Suffix             // Match the end of file names verbatim
+
+	BraceExpr Expr = 9 // Match file names verbatim to any shell brace expansion
 )
 
+// exprName is the string representation of each valid Expr.
+var exprName = map[Expr]string{
+	Fixed:     "fixed",
+	Glob:      "glob",
+	Regexp:    "regexp",
+	Fuzzy:     "fuzzy",
+	Suffix:    "suffix",
+	BraceExpr: "brace",
+}
+
 // String returns a string representation of the receiver Expr e.
 func (e Expr) String() string {
-	if u := uint(e); u < uint(numExpr) {
-		return [numExpr]string{"fixed", "glob", "regexp"}[u]
+	if s, ok := exprName[e]; ok {
+		return s
 	}
 	return ErrInvalidExpr(e).Error()
 }
 
+// MarshalText implements the encoding.TextMarshaler interface's MarshalText
+// method, encoding the receiver Expr e as its string representation.
+func (e Expr) MarshalText() ([]byte, error) {
+	if _, ok := exprName[e]; !ok {
+		return nil, ErrInvalidExpr(e)
+	}
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface's
+// UnmarshalText method, decoding the string representation of an Expr into the
+// receiver *Expr e.
+func (e *Expr) UnmarshalText(text []byte) error {
+	for x, s := range exprName {
+		if s == string(text) {
+			*e = x
+			return nil
+		}
+	}
+	return ErrInvalidExprName(text)
+}
+
+// FuzzyThreshold is the maximum Levenshtein distance between a string and a
+// pattern for which the string matches the pattern according to Fuzzy.
+const FuzzyThreshold = 2
+
 // matchCache is a package-global Cache for use with (Expr).Match.
-var matchCache = Cache{&sync.RWMutex{}, map[string]*regexp.Regexp{}}
+var matchCache = NewCache(0)
+
+// SetCacheSize sets the maximum number of regular expressions retained by the
+// package-global Cache used by Match (0 = unlimited), evicting the least
+// recently used in excess of n. SetCacheSize is safe to call from multiple
+// goroutines concurrently.
+func SetCacheSize(n int) {
+	matchCache.Lock()
+	matchCache.MaxSize = n
+	matchCache.evict()
+	matchCache.Unlock()
+}
+
+// Stats returns the statistics of the package-global Cache used by Match.
+func Stats() CacheStats { return matchCache.Stats() }
 
 // Match reports whether the given string s matches the given string pattern
 // according to the semantics of the receiver Expr e.
@@ -56,8 +118,44 @@ func (e Expr) Match(pattern string, s string) (matched bool, err error) {
 		if r, err = matchCache.Get(pattern); err == nil {
 			matched = r.MatchString(s)
 		}
+	case Fuzzy:
+		matched, err = fuzzy.Levenshtein(pattern, s) <= FuzzyThreshold, nil
+	case Suffix:
+		matched, err = strings.HasSuffix(s, pattern), nil
+	case BraceExpr:
+		for _, p := range BraceExpand(pattern) {
+			if matched = p == s; matched {
+				break
+			}
+		}
 	default:
 		matched, err = false, ErrInvalidExpr(e)
 	}
 	return
 }
+
+// Precompile compiles each of the given regular expression patterns for use
+// with Regexp.Match, returning the error of each pattern at the same index, as
+// with (*Cache).Precompile.
+// Precompile is safe to call from multiple goroutines concurrently.
+func Precompile(pattern []string) []error {
+	return matchCache.Precompile(pattern)
+}
+
+// MatchString reports whether the given string s matches the given string
+// pattern according to the semantics of the given Expr e.
+// It is equivalent to e.Match(pattern, s).
+func MatchString(e Expr, pattern, s string) (bool, error) {
+	return e.Match(pattern, s)
+}
+
+// MustMatchString is like MatchString but panics if the given string pattern
+// is invalid for the given Expr e.
+func MustMatchString(e Expr, pattern, s string) bool {
+	matched, err := e.Match(pattern, s)
+	if err != nil {
+		panic(`expr: MatchString(` + e.String() + `, ` +
+			strconv.Quote(pattern) + `): ` + err.Error())
+	}
+	return matched
+}
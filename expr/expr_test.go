@@ -0,0 +1,83 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		e       Expr
+		pattern string
+		s       string
+		want    bool
+		wantErr bool
+	}{
+		{Fixed, "main.go", "main.go", true, false},
+		{Fixed, "main.go", "main.goo", false, false},
+		{Glob, "*.go", "main.go", true, false},
+		{Glob, "[", "main.go", false, true},
+		{Regexp, `^m.*\.go$`, "main.go", true, false},
+		{Regexp, `^x`, "main.go", false, false},
+		{Regexp, "(", "main.go", false, true},
+		{Fuzzy, "mian.go", "main.go", true, false},
+		{Fuzzy, "util.rs", "main.go", false, false},
+		{Suffix, ".go", "main.go", true, false},
+		{Suffix, ".rs", "main.go", false, false},
+		{BraceExpr, "{main,util}.go", "main.go", true, false},
+		{BraceExpr, "{util,test}.go", "main.go", false, false},
+		{Expr(99), "", "", false, true},
+	} {
+		got, err := MatchString(tc.e, tc.pattern, tc.s)
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("MatchString(%v, %q, %q) = %v, %v", tc.e, tc.pattern, tc.s, got, err)
+		}
+	}
+}
+
+func TestMustMatchString(t *testing.T) {
+	if !MustMatchString(Suffix, ".go", "main.go") {
+		t.Fatal("MustMatchString(Suffix, \".go\", \"main.go\") = false")
+	}
+	defer func() {
+		if r, _ := recover().(string); !strings.Contains(r, "glob") {
+			t.Fatalf("MustMatchString(Glob, \"[\") panicked with %q", r)
+		}
+	}()
+	MustMatchString(Glob, "[", "main.go")
+	t.Fatal("MustMatchString(Glob, \"[\") did not panic")
+}
+
+func TestExprText(t *testing.T) {
+	for e := range exprName {
+		b, err := e.MarshalText()
+		var u Expr
+		if err != nil || u.UnmarshalText(b) != nil || u != e {
+			t.Errorf("%v: MarshalText() = %q, %v; UnmarshalText() = %v", e, b, err, u)
+		}
+	}
+	if _, err := Expr(99).MarshalText(); err != ErrInvalidExpr(99) {
+		t.Errorf("Expr(99).MarshalText() error = %v", err)
+	}
+	if s := Expr(99).String(); s != ErrInvalidExpr(99).Error() {
+		t.Errorf("Expr(99).String() = %q", s)
+	}
+	var u Expr
+	if err := u.UnmarshalText([]byte("lisp")); err != ErrInvalidExprName("lisp") || err.Error() == "" {
+		t.Errorf("UnmarshalText(\"lisp\") error = %v", err)
+	}
+}
+
+func TestPackageCache(t *testing.T) {
+	t.Cleanup(func() { SetCacheSize(0) })
+	if err := Precompile([]string{`^p1`, `^p2`, `^p3`}); err[0] != nil || err[1] != nil || err[2] != nil {
+		t.Fatal(err)
+	}
+	SetCacheSize(2)
+	if s := Stats(); s.Size != 2 {
+		t.Fatalf("Stats().Size = %d after SetCacheSize(2)", s.Size)
+	}
+	if err := Precompile([]string{"("}); err[0] == nil {
+		t.Fatal("Precompile(\"(\") = nil error")
+	}
+}
@@ -0,0 +1,36 @@
+package wh
+
+import (
+	"strings"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// MatchExt returns a list of all files found in each of the given directories
+// sub whose name ends with the given file extension ext (e.g., ".go" or
+// ".pb.go"). A leading '.' is added to ext if not present.
+func MatchExt(option Option, ext string, sub ...string) ([]string, error) {
+	option.Expr = expr.Suffix
+	return Match(option, option.fold(dotExt(ext)), sub...)
+}
+
+// MatchExts returns the result of calling MatchAny with the given list of file
+// extensions exts, each used to match the end of file names verbatim.
+// A leading '.' is added to each extension if not present.
+func MatchExts(option Option, exts []string, sub ...string) ([]string, error) {
+	pattern := make([]string, len(exts))
+	for i, e := range exts {
+		pattern[i] = dotExt(e)
+	}
+	option.Expr = expr.Suffix
+	return MatchAny(option, pattern, sub...)
+}
+
+// dotExt returns the given file extension ext with a leading '.', adding one if
+// not already present.
+func dotExt(ext string) string {
+	if strings.HasPrefix(ext, ".") {
+		return ext
+	}
+	return "." + ext
+}
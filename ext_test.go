@@ -0,0 +1,17 @@
+package wh
+
+import "testing"
+
+func TestMatchExt(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"a.go": "", "b.pb.go": "", "c.GO": "", "d.mod": "", "go": "",
+	})
+	option := DefaultOption()
+	found, err := MatchExt(option, "go", dir)
+	expect(t, found, err, join(dir, "a.go", "b.pb.go")...)
+	found, err = MatchExt(option, ".pb.go", dir)
+	expect(t, found, err, join(dir, "b.pb.go")...)
+	option.IgnoreCase = true
+	found, err = MatchExts(option, []string{"GO", ".mod"}, dir)
+	expect(t, found, err, join(dir, "a.go", "b.pb.go", "c.GO", "d.mod")...)
+}
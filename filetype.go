@@ -0,0 +1,50 @@
+package wh
+
+import "io/fs"
+
+// FileTypeMask is a set of file types recognized by Option.FileTypes.
+type FileTypeMask uint
+
+// File types recognized by Option.FileTypes, which may be combined with bitwise
+// OR. Each corresponds to the fs.FileMode type bits noted.
+const (
+	TypeRegular   FileTypeMask = 1 << iota // No type bits (or an unfollowed symlink)
+	TypeDir                                // fs.ModeDir
+	TypeSymlink                            // fs.ModeSymlink
+	TypeDevice                             // fs.ModeDevice or fs.ModeCharDevice
+	TypeNamedPipe                          // fs.ModeNamedPipe
+	TypeSocket                             // fs.ModeSocket
+)
+
+// fileType returns the FileTypeMask with the single bit corresponding to the
+// type bits of the given fs.FileMode mode.
+//
+// Symlinks are regular files here, because symlinks that were not followed are
+// reported as files; TypeSymlink is instead tested by Option.typed.
+func fileType(mode fs.FileMode) FileTypeMask {
+	switch {
+	case mode&fs.ModeDir != 0:
+		return TypeDir
+	case mode&(fs.ModeDevice|fs.ModeCharDevice) != 0:
+		return TypeDevice
+	case mode&fs.ModeNamedPipe != 0:
+		return TypeNamedPipe
+	case mode&fs.ModeSocket != 0:
+		return TypeSocket
+	}
+	return TypeRegular
+}
+
+// typed reports whether a file is one of the types in option.FileTypes, given
+// the Link l at which it was found and the DirEntry d to which l refers after
+// dereferencing any symlinks. A zero FileTypes is the same as TypeRegular.
+func (option Option) typed(l *Link, d fs.DirEntry) bool {
+	mask := option.FileTypes
+	if mask == 0 {
+		mask = TypeRegular
+	}
+	if mask&TypeSymlink != 0 && l.IsSymlink() {
+		return true
+	}
+	return mask&fileType(d.Type()) != 0
+}
@@ -0,0 +1,218 @@
+package wh
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// accept reports whether the given file d, found via chain and whose name
+// matched the pattern, satisfies each of the constraints specified by option.
+func (option Option) accept(chain Chain, d fs.DirEntry) bool {
+	if !option.visible(chain.Head().ent) {
+		return false
+	}
+	if len(option.Exclude) > 0 && option.excluded(path.Base(chain.Head().Path())) {
+		return false
+	}
+	if option.RequiredParentDir != "" {
+		parent := path.Base(path.Dir(chain.Head().Path()))
+		if parent != option.RequiredParentDir {
+			return false
+		}
+	}
+	if !option.After.IsZero() || !option.Before.IsZero() {
+		info, err := d.Info()
+		if err != nil || !option.modified(info.ModTime()) {
+			return false
+		}
+	}
+	if option.MinSize != 0 || option.MaxSize != 0 {
+		info, err := d.Info()
+		if err != nil || !option.sized(info.Size()) {
+			return false
+		}
+	}
+	if option.DanglingOnly && !dangling(chain.Head()) {
+		return false
+	}
+	if option.HardlinksOnly && !hardlinked(d) {
+		return false
+	}
+	if option.ExecutableOnly && !executable(chain.Tail()) {
+		return false
+	}
+	if option.BinaryOnly || option.ScriptInterpreter != "" {
+		// Read the file once for all constraints on its content.
+		h := head(chain.Tail())
+		if option.BinaryOnly && !executableBinary(h) {
+			return false
+		}
+		if option.ScriptInterpreter != "" && !option.interpreted(h) {
+			return false
+		}
+	}
+	if !option.accessible(chain.Head()) {
+		return false
+	}
+	if !option.owned(d) {
+		return false
+	}
+	if option.staged != nil && !option.staged.contains(option, chain.Head().Path()) {
+		return false
+	}
+	if option.tracked != nil && !option.tracked.contains(option, chain.Head().Path()) {
+		return false
+	}
+	if option.ContentPattern != "" && !option.contains(chain.Tail()) {
+		return false
+	}
+	return true
+}
+
+// modified reports whether the given modification time t is after option.After
+// and before option.Before, ignoring each that is the zero time.
+func (option Option) modified(t time.Time) bool {
+	return (option.After.IsZero() || t.After(option.After)) &&
+		(option.Before.IsZero() || t.Before(option.Before))
+}
+
+// sized reports whether the given file size n is within the range of sizes
+// [option.MinSize, option.MaxSize], where 0 means no bound.
+func (option Option) sized(n int64) bool {
+	return (option.MinSize == 0 || n >= option.MinSize) &&
+		(option.MaxSize == 0 || n <= option.MaxSize)
+}
+
+// excluded reports whether the given file name matches any of the patterns in
+// option.Exclude according to option.Expr and option.IgnoreCase.
+func (option Option) excluded(name string) bool {
+	match := option.Expr.Match
+	if option.Expr == expr.Fuzzy {
+		match = option.fuzzyMatcher()
+	}
+	if option.IgnoreCase {
+		name = strings.ToLower(name)
+	}
+	for _, x := range option.Exclude {
+		if ok, _ := match(option.fold(x), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dangling reports whether the given Link l is a symlink whose target, after
+// resolving all symlinks, does not exist.
+func dangling(l *Link) bool {
+	if !l.IsSymlink() {
+		return false
+	}
+	_, err := l.Stat()
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// hardlinked reports whether the given file d has more than one hard link.
+// Files whose number of links cannot be determined are never hardlinked.
+func hardlinked(d fs.DirEntry) bool {
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	n, ok := nlink(info)
+	return ok && n > 1
+}
+
+// executable reports whether the file referred to by the given Link l, after
+// resolving all symlinks, is an executable file (not a directory) according to
+// its mode or platform.
+func executable(l *Link) bool {
+	info, err := l.Stat()
+	return err == nil && !info.IsDir() && isExecutable(info)
+}
+
+// contains reports whether any line in the file referred to by the given Link l
+// matches the regular expression option.ContentPattern.
+func (option Option) contains(l *Link) bool {
+	f, err := l.Open()
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		if ok, _ := expr.Regexp.Match(option.ContentPattern, scan.Text()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// descend reports whether the given subdirectory d may be searched according to
+// the constraints specified by option.
+func (option Option) descend(d fs.DirEntry) bool {
+	if !option.visible(d) {
+		return false
+	}
+	if len(option.AllowedDirs) > 0 {
+		for _, a := range option.AllowedDirs {
+			if d.Name() == a {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// visible reports whether the given file d may be searched or matched according
+// to whether it is hidden and the constraints specified by option.
+func (option Option) visible(d fs.DirEntry) bool {
+	if option.SkipHidden || option.HiddenOnly {
+		return isHidden(d) == option.HiddenOnly
+	}
+	return true
+}
+
+// Validate returns an error if the values of any fields in option cannot be
+// used together, or otherwise nil.
+func (option Option) Validate() error {
+	if option.SkipHidden && option.HiddenOnly {
+		return ErrConflictingOptions{"SkipHidden", "HiddenOnly"}
+	}
+	if option.MinSize < 0 {
+		return ErrInvalidOption("MinSize")
+	}
+	if option.MaxSize < 0 {
+		return ErrInvalidOption("MaxSize")
+	}
+	if option.MaxSize != 0 && option.MinSize > option.MaxSize {
+		return ErrConflictingOptions{"MinSize", "MaxSize"}
+	}
+	if !option.After.IsZero() && !option.Before.IsZero() && !option.After.Before(option.Before) {
+		return ErrConflictingOptions{"After", "Before"}
+	}
+	if option.filterOwner() && !ownerSupported {
+		return ErrUnsupportedOption("FilterOwner")
+	}
+	if _, _, err := sortLess(option.SortBy); err != nil {
+		return err
+	}
+	if option.ContentPattern != "" {
+		if _, err := regexp.Compile(option.ContentPattern); err != nil {
+			return err
+		}
+	}
+	for _, x := range option.Exclude {
+		if _, err := option.Expr.Match(option.fold(x), ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package wh
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestModifiedRange(t *testing.T) {
+	dir := makeTree(t, map[string]string{"old.txt": "", "new.txt": ""})
+	now := time.Now()
+	old := now.Add(-72 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old.txt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	option := DefaultOption()
+	option.After = now.Add(-24 * time.Hour)
+	found, err := MatchGlob(option, "*.txt", dir)
+	expect(t, found, err, join(dir, "new.txt")...)
+
+	option.After = time.Time{}
+	option.Before = now.Add(-24 * time.Hour)
+	found, err = MatchGlob(option, "*.txt", dir)
+	expect(t, found, err, join(dir, "old.txt")...)
+
+	option.After = now
+	var conflict ErrConflictingOptions
+	if err := option.Validate(); !errors.As(err, &conflict) {
+		t.Fatalf("Validate() = %v, want ErrConflictingOptions", err)
+	}
+}
+
+func TestExclude(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"main.go": "", "main_test.go": "", "util.go": "", "Util_Test.go": "",
+	})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.Exclude = []string{"*_test.go"}
+	found, err := MatchGlob(option, "*.go", dir)
+	expect(t, found, err, join(dir, "Util_Test.go", "main.go", "util.go")...)
+
+	option.IgnoreCase = true
+	found, err = MatchGlob(option, "*.go", dir)
+	expect(t, found, err, join(dir, "main.go", "util.go")...)
+
+	option.IgnoreCase = false
+	option.Expr = expr.Regexp
+	option.Exclude = []string{"^main"}
+	found, err = Match(option, `\.go$`, dir)
+	expect(t, found, err, join(dir, "Util_Test.go", "util.go")...)
+}
+
+func TestSizeRange(t *testing.T) {
+	dir := makeTree(t, map[string]string{"0": "", "1": "1", "10": "0123456789"})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	for _, tt := range []struct {
+		min, max int64
+		want     []string
+	}{
+		{0, 0, []string{"0", "1", "10"}},
+		{1, 0, []string{"1", "10"}}, // MaxSize of 0 has no upper bound
+		{0, 1, []string{"0", "1"}},
+		{1, 1, []string{"1"}},
+		{2, 10, []string{"10"}},
+	} {
+		option.MinSize, option.MaxSize = tt.min, tt.max
+		found, err := MatchGlob(option, "*", dir)
+		expect(t, found, err, join(dir, tt.want...)...)
+	}
+	for _, tt := range []struct {
+		min, max int64
+		field    string
+	}{
+		{-1, 0, "MinSize"},
+		{0, -1, "MaxSize"},
+	} {
+		option.MinSize, option.MaxSize = tt.min, tt.max
+		if _, err := MatchGlob(option, "*", dir); err != ErrInvalidOption(tt.field) {
+			t.Errorf("MinSize %d, MaxSize %d: error = %v, want %v",
+				tt.min, tt.max, err, ErrInvalidOption(tt.field))
+		}
+	}
+}
+
+func TestContentPattern(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"a.txt": "first\nTODO: fix\n", "b.txt": "nothing\n", "sub/c.txt": "TODO\n", "lib/sub/d.txt": "TODO\n",
+	})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 3
+	option.ContentPattern = `^TODO`
+	found, err := Match(option, "*.txt", dir)
+	expect(t, found, err, join(dir, "a.txt", "lib/sub/d.txt", "sub/c.txt")...)
+
+	option.RequiredParentDir = "sub"
+	found, err = Match(option, "*.txt", dir)
+	expect(t, found, err, join(dir, "lib/sub/d.txt", "sub/c.txt")...)
+	option.AllowedDirs = []string{"sub"}
+	found, err = Match(option, "*.txt", dir)
+	expect(t, found, err, join(dir, "sub/c.txt")...)
+
+	option.ContentPattern = "("
+	if _, err := Match(option, "*.txt", dir); err == nil {
+		t.Fatal("Match(invalid ContentPattern) = nil error")
+	}
+}
+
+func TestFileTypes(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a": "", "b/": "", "c/a": ""})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 2
+	option.FileTypes = TypeDir
+	found, err := Match(option, "[abc]", dir)
+	expect(t, found, err, join(dir, "b", "c")...)
+	option.FileTypes = TypeDir | TypeRegular
+	found, err = Match(option, "[abc]", dir)
+	expect(t, found, err, join(dir, "a", "b", "c", "c/a")...)
+	option.FileTypes = 0
+	found, err = Match(option, "[abc]", dir)
+	expect(t, found, err, join(dir, "a", "c/a")...)
+}
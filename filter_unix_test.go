@@ -0,0 +1,45 @@
+//go:build unix
+
+package wh
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestSymlinkFilters(t *testing.T) {
+	dir := makeTree(t, map[string]string{"file": "", "exe": "", "sub/": ""})
+	if err := os.Chmod(join(dir, "exe")[0], 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, dest := range map[string]string{"dangling": "missing", "link": "file", "dir": "sub"} {
+		if err := os.Symlink(dest, join(dir, name)[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.FileTypes = TypeRegular | TypeSymlink
+	found, err := Match(option, "*", dir)
+	expect(t, found, err, join(dir, "dangling", "dir", "exe", "file", "link")...)
+
+	option.DanglingOnly = true
+	found, err = Match(option, "*", dir)
+	expect(t, found, err, join(dir, "dangling")...)
+
+	option.DanglingOnly = false
+	option.ExecutableOnly = true
+	found, err = Match(option, "*", dir)
+	expect(t, found, err, join(dir, "exe")...)
+
+	option.ExecutableOnly = false
+	option.FileTypes = TypeRegular
+	option.FollowSymlinks = true
+	option.MaxFollow = 1
+	found, err = Match(option, "link", dir)
+	if err != nil || len(found) != 1 || found[0] != (&Chain{NewLink(dir, "link", nil), NewLink(dir, "file", nil)}).String() {
+		t.Fatalf("Match(FollowSymlinks) = %q, %v", found, err)
+	}
+}
@@ -0,0 +1,398 @@
+package wh
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrFilterExpr represents an error in which a filter expression given to
+// MatchExpr is invalid.
+type ErrFilterExpr struct {
+	Pos int    // Byte offset in the expression at which the error was found
+	Msg string // Description of the error
+}
+
+// Error returns a descriptive error string for the receiver ErrFilterExpr e.
+func (e ErrFilterExpr) Error() string {
+	return "invalid filter expression at offset " + strconv.Itoa(e.Pos) + ": " + e.Msg
+}
+
+// MatchExpr returns each file found in the given directories sub for which the
+// given boolean filter expression exprStr evaluates to true.
+//
+// Unlike MatchTemplate, the expression can only compare the attributes of each
+// file, and cannot call any function. It consists of the following, in order
+// of increasing precedence:
+//
+//	a || b                 true if either a or b is true
+//	a && b                 true if both a and b are true
+//	!a                     true if a is false
+//	a == b, a != b         equality of any two values of the same type
+//	a < b, a <= b, ...     order of two integers or two strings
+//	(a)                    grouping
+//
+// Values are integers (e.g., 1024), Go string literals (e.g., ".go"), true,
+// false, or one of the following attributes of the file:
+//
+//	Path, Name, Dir, Root    string   (as in Result)
+//	Ext                      string   (extension of Name, including the ".")
+//	Depth, Size, Mode        integer  (Mode contains only permission bits)
+//	ModTime                  integer  (seconds since the Unix epoch)
+//	IsDir, IsSymlink         boolean
+//	IsExecutable             boolean
+//
+// For example, the following expression reports Go files larger than 1 KiB:
+//
+//	Ext == ".go" && Size > 1024
+//
+// The expression is parsed before searching, so that an ErrFilterExpr is
+// returned immediately. Files whose attributes cannot be read are not reported.
+func MatchExpr(option Option, exprStr string, sub ...string) ([]string, error) {
+	node, err := parseFilterExpr(exprStr)
+	if err != nil {
+		return nil, err
+	}
+	option.matcher = func(string, string) (bool, error) { return true, nil }
+	option.predicate = func(r Result) (bool, error) {
+		env := filterEnv{r: r}
+		ok := node.eval(&env).(bool)
+		return ok && env.err == nil, nil
+	}
+	var found []string
+	err = match(context.Background(), option, "", sub, func(r Result) error {
+		found = append(found, r.String())
+		return nil
+	})
+	return found, err
+}
+
+// filterType enumerates the types of values in a filter expression.
+type filterType int
+
+// Enumerated constants of type filterType.
+const (
+	filterBool filterType = iota
+	filterInt
+	filterString
+)
+
+// String returns a string representation of the receiver filterType t.
+func (t filterType) String() string {
+	switch t {
+	case filterBool:
+		return "boolean"
+	case filterInt:
+		return "integer"
+	}
+	return "string"
+}
+
+// filterNode is a node of a parsed filter expression, whose eval function
+// returns a bool, int64, or string according to its typ.
+type filterNode struct {
+	typ  filterType
+	eval func(env *filterEnv) any
+}
+
+// filterEnv contains the file for which a filter expression is evaluated.
+type filterEnv struct {
+	r    Result
+	info fs.FileInfo // Attributes of the file, read when first needed
+	err  error       // Error reading the attributes of the file, if any
+}
+
+// stat returns the attributes of the file, which are read only once, or nil
+// if they cannot be read, in which case env.err is set.
+func (env *filterEnv) stat() fs.FileInfo {
+	if env.info == nil && env.err == nil {
+		env.info, env.err = env.r.Info()
+	}
+	if env.err != nil {
+		return nil
+	}
+	return env.info
+}
+
+// infoFilter returns a filterNode of the given type whose value is returned by
+// the given function f with the attributes of the file, or the given value zero
+// if they cannot be read.
+func infoFilter(typ filterType, zero any, f func(info fs.FileInfo) any) filterNode {
+	return filterNode{typ, func(env *filterEnv) any {
+		if info := env.stat(); info != nil {
+			return f(info)
+		}
+		return zero
+	}}
+}
+
+// filterFields maps the name of each file attribute recognized in a filter
+// expression to the node returning its value.
+var filterFields = map[string]filterNode{
+	"Path": {filterString, func(env *filterEnv) any { return env.r.Path }},
+	"Name": {filterString, func(env *filterEnv) any { return env.r.Name() }},
+	"Dir":  {filterString, func(env *filterEnv) any { return env.r.Dir() }},
+	"Root": {filterString, func(env *filterEnv) any { return env.r.Root }},
+	"Ext":  {filterString, func(env *filterEnv) any { return path.Ext(env.r.Name()) }},
+
+	"Depth":   {filterInt, func(env *filterEnv) any { return int64(env.r.Depth) }},
+	"Size":    infoFilter(filterInt, int64(0), func(info fs.FileInfo) any { return info.Size() }),
+	"Mode":    infoFilter(filterInt, int64(0), func(info fs.FileInfo) any { return int64(info.Mode().Perm()) }),
+	"ModTime": infoFilter(filterInt, int64(0), func(info fs.FileInfo) any { return info.ModTime().Unix() }),
+
+	"IsDir":     infoFilter(filterBool, false, func(info fs.FileInfo) any { return info.IsDir() }),
+	"IsSymlink": {filterBool, func(env *filterEnv) any { return env.r.Chain.Head().IsSymlink() }},
+	"IsExecutable": infoFilter(filterBool, false, func(info fs.FileInfo) any {
+		return !info.IsDir() && isExecutable(info)
+	}),
+}
+
+// filterFieldNames returns the name of each file attribute recognized in a
+// filter expression, in lexical order.
+func filterFieldNames() string {
+	name := make([]string, 0, len(filterFields))
+	for n := range filterFields {
+		name = append(name, n)
+	}
+	sort.Strings(name)
+	return strings.Join(name, ", ")
+}
+
+// filterParser parses a filter expression by recursive descent.
+type filterParser struct {
+	src string
+	pos int    // Byte offset of tok in src
+	tok string // Current token, or empty at end of src
+	end int    // Byte offset following tok in src
+}
+
+// parseFilterExpr returns the root filterNode of the given filter expression s,
+// or an ErrFilterExpr if s is invalid.
+func parseFilterExpr(s string) (node filterNode, err error) {
+	p := &filterParser{src: s}
+	if err = p.next(); err != nil {
+		return
+	}
+	if node, err = p.parseOr(); err != nil {
+		return
+	}
+	if p.tok != "" {
+		return node, p.errorAt("unexpected " + strconv.Quote(p.tok))
+	}
+	if node.typ != filterBool {
+		return node, ErrFilterExpr{Pos: 0, Msg: "expression is not boolean"}
+	}
+	return node, nil
+}
+
+// errorAt returns an ErrFilterExpr with the given message msg at the offset
+// of the current token.
+func (p *filterParser) errorAt(msg string) error {
+	return ErrFilterExpr{Pos: p.pos, Msg: msg}
+}
+
+// next advances to the next token in the expression.
+func (p *filterParser) next() error {
+	i := p.end
+	for i < len(p.src) && unicode.IsSpace(rune(p.src[i])) {
+		i++
+	}
+	p.pos, p.tok = i, ""
+	if i == len(p.src) {
+		p.end = i
+		return nil
+	}
+	j := i + 1
+	switch c := p.src[i]; {
+	case strings.HasPrefix(p.src[i:], "&&"), strings.HasPrefix(p.src[i:], "||"),
+		strings.HasPrefix(p.src[i:], "=="), strings.HasPrefix(p.src[i:], "!="),
+		strings.HasPrefix(p.src[i:], "<="), strings.HasPrefix(p.src[i:], ">="):
+		j = i + 2
+	case strings.ContainsRune("!<>()", rune(c)):
+	case c == '"' || c == '`':
+		q, err := strconv.QuotedPrefix(p.src[i:])
+		if err != nil {
+			return p.errorAt("invalid string literal")
+		}
+		j = i + len(q)
+	case c == '-' || '0' <= c && c <= '9':
+		for j < len(p.src) && '0' <= p.src[j] && p.src[j] <= '9' {
+			j++
+		}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for j < len(p.src) && (p.src[j] == '_' ||
+			unicode.IsLetter(rune(p.src[j])) || unicode.IsDigit(rune(p.src[j]))) {
+			j++
+		}
+	default:
+		return p.errorAt("unexpected " + strconv.QuoteRune(rune(c)))
+	}
+	p.tok, p.end = p.src[i:j], j
+	return nil
+}
+
+// parseLogical parses one or more operands joined by the given logical operator
+// op ("&&" or "||"), each parsed by the given function operand.
+func (p *filterParser) parseLogical(op string, operand func() (filterNode, error)) (filterNode, error) {
+	a, err := operand()
+	if err != nil {
+		return a, err
+	}
+	for p.tok == op {
+		pos := p.pos
+		if err = p.next(); err != nil {
+			return a, err
+		}
+		b, err := operand()
+		if err != nil {
+			return b, err
+		}
+		if a.typ != filterBool || b.typ != filterBool {
+			return a, ErrFilterExpr{Pos: pos, Msg: op + " requires boolean operands"}
+		}
+		x, y := a.eval, b.eval
+		if op == "&&" {
+			a.eval = func(env *filterEnv) any { return x(env).(bool) && y(env).(bool) }
+		} else {
+			a.eval = func(env *filterEnv) any { return x(env).(bool) || y(env).(bool) }
+		}
+	}
+	return a, nil
+}
+
+// parseOr parses one or more operands joined by "||".
+func (p *filterParser) parseOr() (filterNode, error) {
+	return p.parseLogical("||", p.parseAnd)
+}
+
+// parseAnd parses one or more operands joined by "&&".
+func (p *filterParser) parseAnd() (filterNode, error) {
+	return p.parseLogical("&&", p.parseNot)
+}
+
+// parseNot parses a comparison preceded by any number of "!".
+func (p *filterParser) parseNot() (filterNode, error) {
+	if p.tok != "!" {
+		return p.parseCompare()
+	}
+	pos := p.pos
+	if err := p.next(); err != nil {
+		return filterNode{}, err
+	}
+	a, err := p.parseNot()
+	if err != nil {
+		return a, err
+	}
+	if a.typ != filterBool {
+		return a, ErrFilterExpr{Pos: pos, Msg: "! requires a boolean operand"}
+	}
+	x := a.eval
+	a.eval = func(env *filterEnv) any { return !x(env).(bool) }
+	return a, nil
+}
+
+// parseCompare parses a single value, optionally compared with another.
+func (p *filterParser) parseCompare() (filterNode, error) {
+	a, err := p.parseValue()
+	if err != nil {
+		return a, err
+	}
+	op, pos := p.tok, p.pos
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return a, nil
+	}
+	if err = p.next(); err != nil {
+		return a, err
+	}
+	b, err := p.parseValue()
+	if err != nil {
+		return b, err
+	}
+	if a.typ != b.typ {
+		return a, ErrFilterExpr{Pos: pos, Msg: "cannot compare " + a.typ.String() + " with " + b.typ.String()}
+	}
+	if a.typ == filterBool && op != "==" && op != "!=" {
+		return a, ErrFilterExpr{Pos: pos, Msg: op + " requires integer or string operands"}
+	}
+	x, y := a.eval, b.eval
+	return filterNode{filterBool, func(env *filterEnv) any {
+		return compare(x(env), y(env), op)
+	}}, nil
+}
+
+// compare reports whether the given values a and b, both of the same type
+// (bool, int64, or string), satisfy the given comparison operator op.
+func compare(a, b any, op string) bool {
+	c := 0
+	switch a := a.(type) {
+	case bool:
+		if a != b.(bool) {
+			c = 1
+		}
+	case int64:
+		if b := b.(int64); a < b {
+			c = -1
+		} else if a > b {
+			c = 1
+		}
+	case string:
+		c = strings.Compare(a, b.(string))
+	}
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+// parseValue parses a literal, file attribute, or parenthesized expression.
+func (p *filterParser) parseValue() (node filterNode, err error) {
+	tok := p.tok
+	switch {
+	case tok == "":
+		return node, p.errorAt("unexpected end of expression")
+	case tok == "(":
+		if err = p.next(); err != nil {
+			return
+		}
+		if node, err = p.parseOr(); err != nil {
+			return
+		}
+		if p.tok != ")" {
+			return node, p.errorAt("expected )")
+		}
+	case tok == "true" || tok == "false":
+		b := tok == "true"
+		node = filterNode{filterBool, func(*filterEnv) any { return b }}
+	case tok[0] == '"' || tok[0] == '`':
+		s, _ := strconv.Unquote(tok) // Validated by next.
+		node = filterNode{filterString, func(*filterEnv) any { return s }}
+	case tok[0] == '-' || '0' <= tok[0] && tok[0] <= '9':
+		n, nerr := strconv.ParseInt(tok, 10, 64)
+		if nerr != nil {
+			return node, p.errorAt("invalid integer " + strconv.Quote(tok))
+		}
+		node = filterNode{filterInt, func(*filterEnv) any { return n }}
+	default:
+		var ok bool
+		if node, ok = filterFields[tok]; !ok {
+			return node, p.errorAt("unknown attribute " + strconv.Quote(tok) +
+				" (expected " + filterFieldNames() + ")")
+		}
+	}
+	return node, p.next()
+}
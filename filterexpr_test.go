@@ -0,0 +1,68 @@
+package wh
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchExpr(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"a.go":     "hello",
+		"big.go":   strings.Repeat("x", 2000),
+		"b.txt":    "",
+		"sub/c.go": "c",
+	})
+	option := DefaultOption()
+	option.MaxDepth = 2
+	for _, tt := range []struct {
+		expr string
+		want []string
+	}{
+		{`Ext == ".go" && Size > 1024`, []string{"big.go"}},
+		{`Name == "b.txt" || Depth > 1`, []string{"b.txt", "sub/c.go"}},
+		{`!(Ext == ".go")`, []string{"b.txt"}},
+		{`!!(Size == 5)`, []string{"a.go"}},
+		{`Size >= 1 && Size <= 5 && Depth < 2`, []string{"a.go"}},
+		{"Name < `b`", []string{"a.go"}},
+		{`Name != "a.go" && Dir == Root && !IsDir`, []string{"b.txt", "big.go"}},
+		{`ModTime > 0 && Mode > 0 && Size > -1 && true`, []string{"a.go", "b.txt", "big.go", "sub/c.go"}},
+		{`IsSymlink == false && IsExecutable != true && Path != ""`, []string{"a.go", "b.txt", "big.go", "sub/c.go"}},
+		{`false || Size == 1`, []string{"sub/c.go"}},
+	} {
+		found, err := MatchExpr(option, tt.expr, dir)
+		if err != nil {
+			t.Fatalf("MatchExpr(%q): %v", tt.expr, err)
+		}
+		expect(t, found, err, join(dir, tt.want...)...)
+	}
+}
+
+func TestMatchExprError(t *testing.T) {
+	for _, tt := range []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{``, 0, "unexpected end"},
+		{`Size >`, 6, "unexpected end"},
+		{`Size > "x"`, 5, "cannot compare"},
+		{`Name && true`, 5, "boolean operands"},
+		{`true || Size`, 5, "boolean operands"},
+		{`!Size`, 0, "boolean operand"},
+		{`true < false`, 5, "integer or string"},
+		{`(true`, 5, "expected )"},
+		{`Bogus`, 0, "unknown attribute"},
+		{`Size @ 1`, 5, "unexpected '@'"},
+		{`Name == "abc`, 8, "invalid string literal"},
+		{`Size > 99999999999999999999`, 7, "invalid integer"},
+		{`Size > 1 )`, 9, "unexpected \")\""},
+		{`Size`, 0, "not boolean"},
+	} {
+		_, err := MatchExpr(DefaultOption(), tt.expr, ".")
+		var ferr ErrFilterExpr
+		if !errors.As(err, &ferr) || ferr.Pos != tt.pos || !strings.Contains(ferr.Msg, tt.msg) {
+			t.Errorf("MatchExpr(%q) error = %v, want %q at offset %d", tt.expr, err, tt.msg, tt.pos)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package wh
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChainFormatter is the interface implemented by types that can render the
+// Links of a Chain as a string.
+type ChainFormatter interface {
+	Format(links []Link) string
+}
+
+// Built-in implementations of ChainFormatter.
+type (
+	// UnicodeFormatter renders a Chain as a tree drawn with Unicode box-drawing
+	// characters, with one Link per line.
+	UnicodeFormatter struct{}
+	// ASCIIFormatter renders a Chain as a tree drawn with ASCII characters, with
+	// one Link per line.
+	ASCIIFormatter struct{}
+	// PlainFormatter renders only the path of the last Link in a Chain, which is
+	// the file ultimately referred to by all preceding symlinks.
+	PlainFormatter struct{}
+	// ArrowFormatter renders a Chain on a single line, with each Link separated
+	// by an arrow (e.g., "a → b → c").
+	ArrowFormatter struct{}
+)
+
+// Format returns the tree representation of the given links using Unicode
+// box-drawing characters.
+func (UnicodeFormatter) Format(links []Link) string {
+	return formatTree(links, "─┬╼╸", "└┬╼╸", "└─╼╸")
+}
+
+// Format returns the tree representation of the given links using ASCII
+// characters.
+func (ASCIIFormatter) Format(links []Link) string {
+	return formatTree(links, "-+->", "`+->", "`-->")
+}
+
+// Format returns the path of the last of the given links.
+func (PlainFormatter) Format(links []Link) string {
+	if len(links) == 0 {
+		return ""
+	}
+	return links[len(links)-1].Path()
+}
+
+// Format returns the path of each of the given links separated by arrows.
+func (ArrowFormatter) Format(links []Link) string {
+	t := make([]string, len(links))
+	for i := range links {
+		t[i] = links[i].Path()
+	}
+	return strings.Join(t, " → ")
+}
+
+// formatTree returns a tree representation of the given links, with one Link
+// per line, each indented one column deeper than the last and prefixed with
+// the given branch strings for the first, intermediate, and last links.
+// A single Link is represented by its path alone.
+func formatTree(links []Link, first, mid, last string) string {
+	if len(links) == 0 {
+		return ""
+	} else if len(links) == 1 {
+		return links[0].Path()
+	} else {
+		var sb strings.Builder
+		for i := 0; i < len(links); i++ {
+			branch := mid
+			if i == 0 {
+				branch = first
+			} else if i == len(links)-1 {
+				branch = last
+			}
+			fmt.Fprintf(&sb, "%*s%s %s\n", i, "", branch, links[i].Path())
+		}
+		return sb.String()
+	}
+}
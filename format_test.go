@@ -0,0 +1,33 @@
+package wh
+
+import "testing"
+
+func TestFormatters(t *testing.T) {
+	link := []Link{*NewLink("a", "b", nil), *NewLink("c", "d", nil), *NewLink("/e", "f", nil)}
+	for _, tc := range []struct {
+		f    ChainFormatter
+		want [3]string // Zero, one, and three links
+	}{
+		{UnicodeFormatter{}, [3]string{"", "a/b", "─┬╼╸ a/b\n └┬╼╸ c/d\n  └─╼╸ /e/f\n"}},
+		{ASCIIFormatter{}, [3]string{"", "a/b", "-+-> a/b\n `+-> c/d\n  `--> /e/f\n"}},
+		{PlainFormatter{}, [3]string{"", "a/b", "/e/f"}},
+		{ArrowFormatter{}, [3]string{"", "a/b", "a/b → c/d → /e/f"}},
+	} {
+		for i, l := range [][]Link{nil, link[:1], link} {
+			if got := tc.f.Format(l); got != tc.want[i] {
+				t.Errorf("%T.Format(%d links) = %q, want %q", tc.f, len(l), got, tc.want[i])
+			}
+		}
+	}
+	chain := MakeChain(NewLink("a", "b", nil))
+	chain.Add(NewLink("c", "d", nil))
+	if got, want := chain.StringWith(ArrowFormatter{}), "a/b → c/d"; got != want {
+		t.Errorf("Chain.StringWith() = %q, want %q", got, want)
+	}
+	if chain.Head().Path() != "a/b" || chain.Tail().Path() != "c/d" {
+		t.Errorf("Chain.Head(), Tail() = %q, %q", chain.Head().Path(), chain.Tail().Path())
+	}
+	if empty := MakeChain(); empty.Head() != nil || empty.Tail() != nil {
+		t.Error("empty Chain.Head() or Tail() != nil")
+	}
+}
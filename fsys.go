@@ -0,0 +1,102 @@
+package wh
+
+import (
+	"io/fs"
+	"os"
+	"path"
+)
+
+// ReadLinkFS is the interface implemented by a file system that supports
+// reading the destination of symbolic links.
+type ReadLinkFS interface {
+	fs.FS
+	// ReadLink returns the destination of the named symbolic link.
+	ReadLink(name string) (string, error)
+}
+
+// lstatFS is the interface implemented by a file system that supports
+// retrieving the attributes of a file without following symbolic links.
+type lstatFS interface {
+	fs.FS
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+// ErrNoReadLink represents an error in which symbolic links were requested to
+// be followed in a file system that does not implement ReadLinkFS.
+type ErrNoReadLink bool
+
+// Error returns a descriptive error string for the receiver ErrNoReadLink e.
+func (ErrNoReadLink) Error() string {
+	return "file system does not support reading symbolic links"
+}
+
+// MatchFS returns a list of all files found in each of the given directories
+// sub of the given file system fsys whose name matches the given string pattern
+// according to option.Expr.
+// Each directory in sub, and each file returned, is a path in fsys as defined
+// by fs.ValidPath.
+//
+// If option.FollowSymlinks is true, fsys must implement ReadLinkFS, or else
+// ErrNoReadLink is returned. If fsys also implements a method with signature
+// Lstat(name string) (fs.FileInfo, error), it is used to detect symlinks that
+// refer to other symlinks; otherwise, each symlink is dereferenced only once.
+// Symlinks with an absolute destination path are ignored.
+func MatchFS(fsys fs.FS, option Option, pattern string, sub ...string) ([]string, error) {
+	if _, ok := fsys.(ReadLinkFS); option.FollowSymlinks && !ok {
+		return nil, ErrNoReadLink(true)
+	}
+	option.fsys = fsys
+	return Match(option, pattern, sub...)
+}
+
+// sub returns the file system rooted at the given directory root of the file
+// system searched according to option.
+func (option Option) sub(root string) (fs.FS, error) {
+	if option.fsys == nil {
+		return os.DirFS(root), nil
+	}
+	return fs.Sub(option.fsys, root)
+}
+
+// readlink returns the destination of the receiver symlink l.
+func (l *Link) readlink() (string, error) {
+	if l.fsys == nil {
+		return os.Readlink(l.Path())
+	}
+	if rl, ok := l.fsys.(ReadLinkFS); ok {
+		return rl.ReadLink(l.Path())
+	}
+	return "", &fs.PathError{Op: "readlink", Path: l.Path(), Err: ErrNoReadLink(true)}
+}
+
+// lstat returns the attributes of the named file in the file system of the
+// receiver Link l, without following symlinks if supported by the file system.
+func (l *Link) lstat(name string) (fs.FileInfo, error) {
+	if l.fsys == nil {
+		return os.Lstat(name)
+	}
+	if path.IsAbs(name) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+	if ls, ok := l.fsys.(lstatFS); ok {
+		return ls.Lstat(name)
+	}
+	return fs.Stat(l.fsys, name)
+}
+
+// Stat returns the attributes of the file referred to by the receiver Link l,
+// following symlinks.
+func (l *Link) Stat() (fs.FileInfo, error) {
+	if l.fsys == nil {
+		return os.Stat(l.Path())
+	}
+	return fs.Stat(l.fsys, l.Path())
+}
+
+// Open opens the file referred to by the receiver Link l for reading.
+func (l *Link) Open() (fs.File, error) {
+	if l.fsys == nil {
+		return os.Open(l.Path())
+	}
+	return l.fsys.Open(l.Path())
+}
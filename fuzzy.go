@@ -0,0 +1,30 @@
+package wh
+
+import (
+	"strings"
+
+	"github.com/ardnew/wh/expr"
+	"github.com/ardnew/wh/fuzzy"
+)
+
+// MatchFuzzy returns the result of calling Match with the given string pattern
+// used to match file names whose Levenshtein distance from the pattern is no
+// greater than option.FuzzyThreshold.
+//
+// The distance is measured in runes, not bytes, so that a single substitution
+// of a multi-byte character has distance 1.
+func MatchFuzzy(option Option, pattern string, sub ...string) ([]string, error) {
+	option.Expr = expr.Fuzzy
+	if option.IgnoreCase {
+		pattern = strings.ToLower(pattern)
+	}
+	return Match(option, pattern, sub...)
+}
+
+// fuzzyMatcher returns a matchFunc reporting whether a file name is within
+// option.FuzzyThreshold edits of a pattern.
+func (option Option) fuzzyMatcher() matchFunc {
+	return func(pattern, name string) (bool, error) {
+		return fuzzy.Levenshtein(pattern, name) <= option.FuzzyThreshold, nil
+	}
+}
@@ -0,0 +1,110 @@
+// Package fuzzy defines measures of the similarity of strings.
+package fuzzy
+
+// Levenshtein returns the Levenshtein distance between strings a and b, which
+// is the minimum number of single-rune insertions, deletions, and substitutions
+// required to change one string into the other.
+//
+// The distance is computed with the Wagner-Fischer algorithm in O(m*n) time,
+// retaining only a single row of the distance matrix in O(min(m,n)) space.
+func Levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	if len(s) < len(t) {
+		s, t = t, s // Use the shorter string for the row.
+	}
+	row := make([]int, len(t)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		diag := row[0] // Distance of row[i-1][j-1]
+		row[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			next := min(row[j]+1, row[j-1]+1, diag+cost)
+			diag, row[j] = row[j], next
+		}
+	}
+	return row[len(t)]
+}
+
+// Jaro returns the Jaro similarity of strings a and b, from 0 (no similarity)
+// to 1 (identical).
+func Jaro(a, b string) float64 {
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 && len(t) == 0 {
+		return 1
+	}
+	if len(s) == 0 || len(t) == 0 {
+		return 0
+	}
+	// Runes match only if equal and no farther apart than window.
+	window := max(len(s), len(t))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	sm, tm := make([]bool, len(s)), make([]bool, len(t))
+	m := 0
+	for i := range s {
+		lo, hi := max(0, i-window), min(len(t), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !tm[j] && s[i] == t[j] {
+				sm[i], tm[j] = true, true
+				m++
+				break
+			}
+		}
+	}
+	if m == 0 {
+		return 0
+	}
+	// Count half the number of matching runes that are out of order.
+	k, n := 0, 0
+	for i := range s {
+		if sm[i] {
+			for !tm[k] {
+				k++
+			}
+			if s[i] != t[k] {
+				n++
+			}
+			k++
+		}
+	}
+	f := float64(m)
+	return (f/float64(len(s)) + f/float64(len(t)) + (f-float64(n/2))/f) / 3
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of strings a and b, from 0
+// (no similarity) to 1 (identical), which is the Jaro similarity increased for
+// strings sharing a common prefix of up to 4 runes.
+func JaroWinkler(a, b string) float64 {
+	const (
+		scale  = 0.1 // Standard scaling factor
+		prefix = 4   // Maximum length of common prefix
+	)
+	j := Jaro(a, b)
+	s, t := []rune(a), []rune(b)
+	l := 0
+	for l < prefix && l < len(s) && l < len(t) && s[l] == t[l] {
+		l++
+	}
+	return j + float64(l)*scale*(1-j)
+}
+
+// BestMatch returns the string in candidates with the least Levenshtein
+// distance to pattern, along with its distance. The first such candidate is
+// returned if there are multiple. If candidates is empty, BestMatch returns
+// the empty string and -1.
+func BestMatch(pattern string, candidates []string) (string, int) {
+	best, dist := "", -1
+	for _, c := range candidates {
+		if d := Levenshtein(pattern, c); dist < 0 || d < dist {
+			best, dist = c, d
+		}
+	}
+	return best, dist
+}
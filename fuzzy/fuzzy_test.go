@@ -0,0 +1,62 @@
+package fuzzy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"kitten", "sitting", 3},
+		{"sitting", "kitten", 3},
+		{"saturday", "sunday", 3},
+		{"flaw", "lawn", 2},
+		{"gumbo", "gambol", 2},
+		{"intention", "execution", 5},
+		{"café", "cafe", 1}, // Distance is measured in runes, not bytes.
+		{"日本語", "日本", 1},
+	} {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	// Values given by Winkler (1990) and Cohen, Ravikumar, and Fienberg (2003).
+	for _, tt := range []struct {
+		a, b        string
+		jaro, jaroW float64
+	}{
+		{"", "", 1, 1},
+		{"abc", "", 0, 0},
+		{"abc", "abc", 1, 1},
+		{"abc", "xyz", 0, 0},
+		{"MARTHA", "MARHTA", 0.944, 0.961},
+		{"DWAYNE", "DUANE", 0.822, 0.840},
+		{"DIXON", "DICKSONX", 0.767, 0.813},
+	} {
+		if got := Jaro(tt.a, tt.b); math.Abs(got-tt.jaro) > 0.001 {
+			t.Errorf("Jaro(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.jaro)
+		}
+		if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.jaroW) > 0.001 {
+			t.Errorf("JaroWinkler(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.jaroW)
+		}
+	}
+}
+
+func TestBestMatch(t *testing.T) {
+	if best, dist := BestMatch("gofmt", nil); best != "" || dist != -1 {
+		t.Errorf("BestMatch(nil) = %q, %d", best, dist)
+	}
+	best, dist := BestMatch("gofmt", []string{"go", "gofmtx", "gofnt", "fmt"})
+	if best != "gofmtx" || dist != 1 {
+		t.Errorf("BestMatch() = %q, %d, want %q, 1", best, dist, "gofmtx")
+	}
+}
@@ -0,0 +1,35 @@
+package wh
+
+import (
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestMatchFuzzyUnicode(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"naive.txt": "",
+		"naïve.txt": "", // "ï" is 2 bytes but 1 rune
+		"日本語.txt":   "", // Each of "日本語" is 3 bytes but 1 rune
+		"other.txt": "",
+	})
+	option := DefaultOption()
+
+	option.FuzzyThreshold = 0
+	found, err := MatchFuzzy(option, "naive.txt", dir)
+	expect(t, found, err, join(dir, "naive.txt")...)
+
+	option.FuzzyThreshold = 1
+	found, err = MatchFuzzy(option, "naive.txt", dir)
+	expect(t, found, err, join(dir, "naive.txt", "naïve.txt")...)
+	found, err = MatchFuzzy(option, "日本人.txt", dir)
+	expect(t, found, err, join(dir, "日本語.txt")...)
+
+	option.IgnoreCase = true
+	found, err = MatchFuzzy(option, "NAÏVE.TXT", dir)
+	expect(t, found, err, join(dir, "naive.txt", "naïve.txt")...)
+
+	if ok, err := expr.Fuzzy.Match("日本人.txt", "日本語.txt"); !ok || err != nil {
+		t.Fatalf("Fuzzy.Match() = %t, %v", ok, err)
+	}
+}
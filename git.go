@@ -0,0 +1,73 @@
+package wh
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+)
+
+// ErrNotGitRepo represents an error in which a git-aware search was requested
+// in a directory that is not contained in a git repository.
+type ErrNotGitRepo string
+
+// Error returns a descriptive error string for the receiver ErrNotGitRepo e.
+func (e ErrNotGitRepo) Error() string {
+	return "not a git repository: " + string(e)
+}
+
+// gitSet is a set of absolute file paths reported by git.
+type gitSet map[string]bool
+
+// gitFiles returns the set of absolute paths of each file listed by running git
+// with the given arguments in the given directory dir, which must be contained
+// in a git repository. The arguments must cause git to list null-terminated
+// paths relative to the top-level directory of the repository.
+func gitFiles(ctx context.Context, dir string, arg ...string) (gitSet, error) {
+	top, err := exec.CommandContext(ctx, "git", "-C", dir,
+		"rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, ErrNotGitRepo(dir)
+	}
+	root := string(bytes.TrimRight(top, "\r\n"))
+	out, err := exec.CommandContext(ctx, "git",
+		append([]string{"-C", dir}, arg...)...).Output()
+	if err != nil {
+		return nil, err
+	}
+	set := gitSet{}
+	for _, name := range bytes.Split(out, []byte{0}) {
+		if len(name) > 0 {
+			set[filepath.Join(root, filepath.FromSlash(string(name)))] = true
+		}
+	}
+	return set, nil
+}
+
+// gitStaged returns the set of absolute paths of each file staged in the index
+// of the git repository containing the given directory dir.
+func gitStaged(ctx context.Context, dir string) (gitSet, error) {
+	return gitFiles(ctx, dir, "diff", "--cached", "--name-only", "-z")
+}
+
+// gitTracked returns the set of absolute paths of each file either tracked or
+// untracked but not ignored in the git repository containing the given
+// directory dir.
+func gitTracked(ctx context.Context, dir string) (gitSet, error) {
+	return gitFiles(ctx, dir, "ls-files", "--cached", "--others",
+		"--exclude-standard", "--full-name", "-z")
+}
+
+// contains reports whether the set contains the file at the given path p,
+// which is interpreted relative to option.WorkingDir if not absolute.
+// The path is compared with all symlinks resolved if not found verbatim.
+func (s gitSet) contains(option Option, p string) bool {
+	p = option.abs(p)
+	if s[p] {
+		return true
+	}
+	if real, err := filepath.EvalSymlinks(p); err == nil {
+		return s[real]
+	}
+	return false
+}
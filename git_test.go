@@ -0,0 +1,44 @@
+package wh
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestGitOnly(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip(err)
+	}
+	dir := makeTree(t, map[string]string{
+		".gitignore": "*.log\n", "a.go": "", "b.go": "", "c.log": "",
+	})
+	for _, arg := range [][]string{{"init", "-q"}, {"add", "a.go"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, arg...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %q: %v: %s", arg, err, out)
+		}
+	}
+	chdir(t, dir)
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.GitTrackedOnly = true
+	found, err := Match(option, "[abc].*", ".")
+	expect(t, found, err, "a.go", "b.go")
+
+	option.GitTrackedOnly = false
+	option.GitStagedOnly = true
+	found, err = Match(option, "[abc].*", ".")
+	expect(t, found, err, "a.go")
+
+	outside := t.TempDir()
+	chdir(t, outside)
+	if _, err := Match(option, "*", "."); err == nil {
+		t.Fatal("Match(GitStagedOnly) outside repository = nil error")
+	}
+	option.GitStagedOnly = false
+	option.GitTrackedOnly = true
+	if _, err := Match(option, "*", outside); err == nil {
+		t.Fatal("Match(GitTrackedOnly) outside repository = nil error")
+	}
+}
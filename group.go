@@ -0,0 +1,49 @@
+package wh
+
+import "context"
+
+// MatchAnyDir returns the files matching the given string pattern according to
+// option.Expr, partitioned by the directory in sub from which each file was
+// found.
+//
+// Each directory in sub is searched independently and is present as a key in
+// the returned map only if at least one matching file was found within it.
+// The errors encountered while searching each directory are returned together
+// in an ErrWalkDir, but any other error stops the search immediately.
+func MatchAnyDir(option Option, pattern string, sub ...string) (map[string][]string, error) {
+	found := map[string][]string{}
+	serr := ErrWalkDir{}
+	for _, root := range sub {
+		err := match(context.Background(), option, option.fold(pattern), []string{root},
+			func(r Result) error {
+				found[root] = append(found[root], r.String())
+				return nil
+			})
+		if e, ok := err.(ErrWalkDir); ok {
+			serr = append(serr, e...)
+		} else if err != nil {
+			// Not specific to this directory (e.g., an invalid option or pattern).
+			return found, err
+		}
+	}
+	if len(serr) > 0 {
+		return found, serr
+	}
+	return found, nil
+}
+
+// MatchWithDir returns the base name of each file matching the given string
+// pattern according to option.Expr, partitioned by the directory containing the
+// file.
+//
+// Unlike MatchAnyDir, which partitions files by the directory in sub in which
+// they were found, files found in subdirectories of a directory in sub are
+// keyed by their own parent directory.
+func MatchWithDir(option Option, pattern string, sub ...string) (map[string][]string, error) {
+	found := map[string][]string{}
+	err := match(context.Background(), option, option.fold(pattern), sub, func(r Result) error {
+		found[r.Dir()] = append(found[r.Dir()], r.Name())
+		return nil
+	})
+	return found, err
+}
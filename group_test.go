@@ -0,0 +1,56 @@
+package wh
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestMatchGroups(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"x/a.go": "", "x/sub/b.go": "", "y/c.go": "", "z/d.txt": "",
+	})
+	x, y, z := filepath.Join(dir, "x"), filepath.Join(dir, "y"), filepath.Join(dir, "z")
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 2
+
+	byRoot, err := MatchAnyDir(option, "*.go", x, y, z)
+	want := map[string][]string{x: join(dir, "x/a.go", "x/sub/b.go"), y: join(dir, "y/c.go")}
+	if err != nil || !reflect.DeepEqual(byRoot, want) {
+		t.Fatalf("MatchAnyDir() = %q, %v, want %q", byRoot, err, want)
+	}
+	if _, err := MatchAnyDir(option, "*.go", filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("MatchAnyDir(missing) = nil error")
+	}
+
+	byDir, err := MatchWithDir(option, "*.go", x, y)
+	want = map[string][]string{x: {"a.go"}, filepath.Join(x, "sub"): {"b.go"}, y: {"c.go"}}
+	if err != nil || !reflect.DeepEqual(byDir, want) {
+		t.Fatalf("MatchWithDir() = %q, %v, want %q", byDir, err, want)
+	}
+}
+
+func TestMatchAnyDirError(t *testing.T) {
+	dir := makeTree(t, map[string]string{"x/a.go": "", "y/b.go": ""})
+	option := DefaultOption()
+	option.SkipHidden, option.HiddenOnly = true, true
+	found, err := MatchAnyDir(option, "a.go", join(dir, "x", "y")...)
+	if _, ok := err.(ErrConflictingOptions); !ok || len(found) != 0 {
+		t.Fatalf("MatchAnyDir(conflicting options) = %q, %v", found, err)
+	}
+
+	option = DefaultOption()
+	option.Expr = expr.Glob
+	if _, err := MatchAnyDir(option, "[", join(dir, "x")...); err == nil {
+		t.Fatal("MatchAnyDir(\"[\") = nil error")
+	}
+
+	option.MaxDepth = 2
+	option.MaxDirs = 1
+	if _, err := MatchAnyDir(option, "*.go", dir); err != ErrMaxDirs(1) {
+		t.Fatalf("MatchAnyDir(MaxDirs 1) error = %v, want %v", err, ErrMaxDirs(1))
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package wh
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// isHidden reports whether the given file d is hidden, which is true for any
+// file whose name begins with '.' by Unix convention.
+func isHidden(d fs.DirEntry) bool {
+	return strings.HasPrefix(d.Name(), ".")
+}
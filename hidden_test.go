@@ -0,0 +1,36 @@
+package wh
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestHidden(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		".rc":       "",
+		"file":      "",
+		".dir/.rc":  "",
+		".dir/file": "",
+		"dir/.rc":   "",
+		"dir/file":  "",
+	})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 2
+
+	option.SkipHidden = true
+	found, err := Match(option, "*", dir)
+	expect(t, found, err, join(dir, "dir/file", "file")...)
+
+	option.SkipHidden, option.HiddenOnly = false, true
+	found, err = Match(option, "*", dir)
+	expect(t, found, err, join(dir, ".dir/.rc", ".rc")...)
+
+	option.SkipHidden = true
+	var conflict ErrConflictingOptions
+	if _, err := Match(option, "*", dir); !errors.As(err, &conflict) {
+		t.Fatalf("Match() error = %v, want ErrConflictingOptions", err)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package wh
+
+import (
+	"io/fs"
+	"strings"
+	"syscall"
+)
+
+// isHidden reports whether the given file d is hidden, which is true for any
+// file with the FILE_ATTRIBUTE_HIDDEN attribute set. For portability with Unix
+// conventions, any file whose name begins with '.' is also considered hidden.
+func isHidden(d fs.DirEntry) bool {
+	if strings.HasPrefix(d.Name(), ".") {
+		return true
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	if a, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return a.FileAttributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+	}
+	return false
+}
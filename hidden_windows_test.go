@@ -0,0 +1,30 @@
+package wh
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestHiddenAttribute(t *testing.T) {
+	dir := makeTree(t, map[string]string{"hidden": "", "file": ""})
+	name, err := syscall.UTF16PtrFromString(filepath.Join(dir, "hidden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.SetFileAttributes(name, syscall.FILE_ATTRIBUTE_HIDDEN); err != nil {
+		t.Fatal(err)
+	}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+
+	option.HiddenOnly = true
+	found, err := Match(option, "*", dir)
+	expect(t, found, err, join(dir, "hidden")...)
+
+	option.SkipHidden, option.HiddenOnly = true, false
+	found, err = Match(option, "*", dir)
+	expect(t, found, err, join(dir, "file")...)
+}
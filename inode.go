@@ -0,0 +1,31 @@
+package wh
+
+import "context"
+
+// FindInode returns each file found in the given directories sub that resides
+// on the device with the given ID dev and has the given inode number ino, such
+// as each hard link to a file whose name is unknown. It is the inverse of
+// comparing a file with os.SameFile: the file is identified by its raw device
+// and inode numbers rather than by a reference path.
+//
+// Only files of the types in option.FileTypes are compared, and each followed
+// symlink is compared by the file to which it refers. No file is found on
+// platforms that do not report device and inode numbers (e.g., Windows).
+func FindInode(dev, ino uint64, option Option, sub ...string) ([]string, error) {
+	option.matcher = func(string, string) (bool, error) { return true, nil }
+	option.predicate = func(r Result) (bool, error) {
+		info, err := r.Info()
+		if err != nil {
+			return false, nil
+		}
+		d, dok := device(info)
+		i, iok := inode(info)
+		return dok && iok && d == dev && i == ino, nil
+	}
+	var found []string
+	err := match(context.Background(), option, "", sub, func(r Result) error {
+		found = append(found, r.String())
+		return nil
+	})
+	return found, err
+}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package wh
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestFindInode(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a": "", "b": "", "sub/": ""})
+	a, c := join(dir, "a")[0], join(dir, "sub/c")[0]
+	if err := os.Link(a, c); err != nil {
+		t.Skip(err)
+	}
+	info, err := os.Stat(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := info.Sys().(*syscall.Stat_t)
+	option := DefaultOption()
+	option.MaxDepth = 2
+	found, err := FindInode(uint64(st.Dev), uint64(st.Ino), option, dir)
+	expect(t, found, err, a, c)
+
+	option.HardlinksOnly = true
+	found, err = Match(option, "a", dir)
+	expect(t, found, err, a)
+	found, err = Match(option, "b", dir)
+	expect(t, found, err)
+}
@@ -0,0 +1,89 @@
+package wh
+
+import (
+	"context"
+	"sync"
+)
+
+// NextFunc returns the next file found by a search and true, or a zero Result
+// and false if the search is complete.
+type NextFunc func() (Result, bool)
+
+// MatchIter returns a NextFunc that returns each file found in the given
+// directories sub whose name matches the given string pattern according to
+// option.Expr, and a function done that stops the search and returns the error
+// encountered while searching, if any.
+//
+// The search is lazy: it does not start until the first call to next, and it
+// does not advance beyond each file found until the next call to next. The
+// caller must call done once it no longer calls next, unless next returned
+// false or the given context ctx is done, to release the resources used by the
+// search.
+func MatchIter(ctx context.Context, option Option, pattern string, sub ...string) (next NextFunc, done func() error) {
+	ictx, cancel := context.WithCancel(ctx)
+	res := make(chan Result)
+	var err error
+	var start sync.Once
+	walk := func() {
+		go func() {
+			defer close(res)
+			err = match(ictx, option, option.fold(pattern), sub, func(r Result) error {
+				select {
+				case res <- r:
+					return nil
+				case <-ictx.Done():
+					return ictx.Err()
+				}
+			})
+		}()
+	}
+	next = func() (Result, bool) {
+		start.Do(walk)
+		r, ok := <-res
+		return r, ok
+	}
+	done = func() error {
+		cancel()
+		start.Do(func() { close(res) }) // Never started; nothing to wait for.
+		for range res {
+			// Wait for the search to stop.
+		}
+		if ctx.Err() == nil {
+			// Remove the errors caused by stopping the search.
+			return withoutCanceled(err)
+		}
+		return err
+	}
+	return
+}
+
+// MatchChan sends each file found in the given directories sub whose name
+// matches the given string pattern according to option.Expr on the returned
+// results channel as soon as it is found.
+//
+// Once the search is complete, the error encountered while searching, if any,
+// is sent on the returned error channel, and then both channels are closed.
+// The caller must not close either channel. To stop the search early, cancel
+// the given context ctx; the search then stops without waiting for the caller
+// to receive any more files. The caller must cancel ctx if it stops receiving
+// before the results channel is closed, or else the search never completes.
+func MatchChan(ctx context.Context, option Option, pattern string, sub ...string) (<-chan string, <-chan error) {
+	res := make(chan string)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(res)
+		err := match(ctx, option, option.fold(pattern), sub, func(r Result) error {
+			select {
+			case res <- r.String():
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return res, errc
+}
@@ -0,0 +1,72 @@
+package wh
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// goroutinesSettle fails the test if the number of goroutines does not return
+// to at most the given count n within a second.
+func goroutinesSettle(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines = %d, want at most %d", runtime.NumGoroutine(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMatchChan(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.txt": "", "b.txt": "", "c.txt": ""})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+
+	var found []string
+	res, errc := MatchChan(context.Background(), option, "*.txt", dir)
+	for f := range res {
+		found = append(found, f)
+	}
+	expect(t, found, <-errc, join(dir, "a.txt", "b.txt", "c.txt")...)
+}
+
+func TestMatchChanPartialRead(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.txt": "", "b.txt": "", "c.txt": ""})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+
+	n := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	res, errc := MatchChan(ctx, option, "*.txt", dir)
+	if f := <-res; f != join(dir, "a.txt")[0] {
+		t.Fatalf("first result = %q", f)
+	}
+	cancel() // Discard the remaining results.
+	goroutinesSettle(t, n)
+	for err := range errc {
+		if err == nil {
+			t.Fatal("received nil error")
+		}
+	}
+}
+
+func TestMatchIterDone(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.txt": "", "b.txt": "", "c.txt": ""})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+
+	n := runtime.NumGoroutine()
+	next, done := MatchIter(context.Background(), option, "*.txt", dir)
+	if r, ok := next(); !ok || r.Name() != "a.txt" {
+		t.Fatalf("next() = %v, %t", r, ok)
+	}
+	if err := done(); err != nil {
+		t.Fatal(err)
+	}
+	goroutinesSettle(t, n)
+}
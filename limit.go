@@ -0,0 +1,48 @@
+package wh
+
+import "context"
+
+// matchLimit calls match with a visitFunc that calls the given visitFunc visit
+// for at most option.MaxResults files, after which the search is stopped.
+func matchLimit(ctx context.Context, option Option, pattern string, sub []string, visit visitFunc) error {
+	lctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	n := option.MaxResults
+	option.MaxResults = 0
+	err := match(lctx, option, pattern, sub, func(r Result) error {
+		if n <= 0 {
+			return nil
+		}
+		n--
+		if err := visit(r); err != nil {
+			return err
+		}
+		if n == 0 {
+			cancel() // Stop all processing; we have enough results.
+		}
+		return nil
+	})
+	if n == 0 && ctx.Err() == nil {
+		// Remove the errors caused by stopping the search.
+		return withoutCanceled(err)
+	}
+	return err
+}
+
+// withoutCanceled returns the given error err without each of its walk errors
+// caused by canceling a context, or nil if no other errors remain.
+func withoutCanceled(err error) error {
+	if serr, ok := err.(ErrWalkDir); ok {
+		keep := ErrWalkDir{}
+		for _, e := range serr {
+			if e.err != context.Canceled {
+				keep = append(keep, e)
+			}
+		}
+		if len(keep) == 0 {
+			return nil
+		}
+		return keep
+	}
+	return err
+}
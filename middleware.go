@@ -0,0 +1,119 @@
+package wh
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// MatchMiddleware is the signature of a function that wraps a MatchFunc with
+// additional processing before and/or after it is called.
+type MatchMiddleware func(MatchFunc) MatchFunc
+
+// ApplyMiddleware returns the given MatchFunc fn wrapped by each of the given
+// MatchMiddleware mw, such that the first middleware is the outermost wrapper.
+// That is, the first middleware is called first, and the last middleware calls
+// fn directly.
+func ApplyMiddleware(fn MatchFunc, mw ...MatchMiddleware) MatchFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		fn = mw[i](fn)
+	}
+	return fn
+}
+
+// LoggingMiddleware returns a MatchMiddleware that logs each call to the given
+// logger, including the pattern, the searched directories, the number of files
+// found, the duration of the call, and any error returned.
+func LoggingMiddleware(logger *slog.Logger) MatchMiddleware {
+	return func(fn MatchFunc) MatchFunc {
+		return func(option Option, pattern string, sub ...string) ([]string, error) {
+			start := time.Now()
+			found, err := fn(option, pattern, sub...)
+			attr := []any{
+				slog.String("pattern", pattern),
+				slog.Any("dirs", sub),
+				slog.Int("found", len(found)),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				logger.Error("match", append(attr, slog.Any("error", err))...)
+			} else {
+				logger.Info("match", attr...)
+			}
+			return found, err
+		}
+	}
+}
+
+// cacheEntry is a result cached by CachingMiddleware.
+type cacheEntry struct {
+	found   []string
+	expires time.Time
+}
+
+// CachingMiddleware returns a MatchMiddleware that caches the files found by
+// each call for the given duration ttl, keyed by the Option, pattern, and
+// searched directories of the call.
+//
+// Only calls that return no error are cached. Calls with an Option having any
+// function, writer, or statistics field set (e.g., TraceHook) are never cached,
+// since the effects of such fields cannot be reproduced from the cache. Each
+// MatchFunc wrapped by the returned MatchMiddleware has its own cache, from
+// which expired entries are removed on each call.
+func CachingMiddleware(ttl time.Duration) MatchMiddleware {
+	return func(fn MatchFunc) MatchFunc {
+		var mu sync.Mutex
+		cache := map[string]cacheEntry{}
+		return func(option Option, pattern string, sub ...string) ([]string, error) {
+			key, ok := option.cacheKey()
+			if !ok {
+				return fn(option, pattern, sub...)
+			}
+			key += fmt.Sprintf("\x00%q\x00%q", pattern, sub)
+			now := time.Now()
+			mu.Lock()
+			for k, e := range cache {
+				if !now.Before(e.expires) {
+					delete(cache, k)
+				}
+			}
+			e, ok := cache[key]
+			mu.Unlock()
+			if ok {
+				return append([]string{}, e.found...), nil
+			}
+			found, err := fn(option, pattern, sub...)
+			if err == nil {
+				mu.Lock()
+				cache[key] = cacheEntry{
+					found:   append([]string{}, found...),
+					expires: time.Now().Add(ttl),
+				}
+				mu.Unlock()
+			}
+			return found, err
+		}
+	}
+}
+
+// cacheKey returns a string identifying the value of each field of the
+// receiver Option option that affects the files found, and true, or the empty
+// string and false if option has any field whose effects cannot be identified
+// by value, such as a function or file system.
+func (option Option) cacheKey() (string, bool) {
+	if option.WalkErrHandler != nil || option.TraceHook != nil ||
+		option.Stats != nil || option.AuditLog != nil ||
+		option.fsys != nil || option.matcher != nil || option.predicate != nil ||
+		option.staged != nil || option.tracked != nil ||
+		option.dirs != nil || option.pre != nil {
+		return "", false
+	}
+	// Each exported field other than those above is encoded by MarshalJSON.
+	b, err := option.MarshalJSON()
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s\x00%d\x00%d\x00%q\x00%v", b,
+		option.fromDepth, option.fromFollow, option.module, option.ancestors), true
+}
@@ -0,0 +1,127 @@
+package wh
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestCachingMiddleware(t *testing.T) {
+	var calls int
+	fixed := func(option Option, pattern string, sub ...string) ([]string, error) {
+		calls++
+		return []string{"fixed"}, nil
+	}
+	glob := func(option Option, pattern string, sub ...string) ([]string, error) {
+		calls++
+		return []string{"glob"}, nil
+	}
+	mw := CachingMiddleware(time.Hour)
+	cf, cg := mw(fixed), mw(glob)
+	for i := 0; i < 2; i++ {
+		found, err := cf(Option{}, "x", ".")
+		expect(t, found, err, "fixed")
+		found, err = cg(Option{}, "x", ".")
+		expect(t, found, err, "glob")
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2", calls)
+	}
+
+	calls = 0
+	ce := CachingMiddleware(-time.Second)(fixed)
+	for i := 0; i < 2; i++ {
+		found, err := ce(Option{}, "x", ".")
+		expect(t, found, err, "fixed")
+	}
+	if calls != 2 {
+		t.Fatalf("calls with expired entries = %d, want 2", calls)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	var order []string
+	tag := func(s string) MatchMiddleware {
+		return func(fn MatchFunc) MatchFunc {
+			return func(option Option, pattern string, sub ...string) ([]string, error) {
+				order = append(order, s)
+				return fn(option, pattern, sub...)
+			}
+		}
+	}
+	fail := errors.New("fail")
+	fn := ApplyMiddleware(func(option Option, pattern string, sub ...string) ([]string, error) {
+		if pattern == "bad" {
+			return nil, fail
+		}
+		return []string{"a", "b"}, nil
+	}, tag("outer"), LoggingMiddleware(logger), tag("inner"))
+
+	if found, err := fn(DefaultOption(), "ok", "dir"); err != nil || len(found) != 2 {
+		t.Fatalf("fn(\"ok\") = %q, %v", found, err)
+	}
+	if !strings.Contains(buf.String(), "level=INFO msg=match pattern=ok dirs=[dir] found=2") {
+		t.Fatalf("log = %q", buf.String())
+	}
+	buf.Reset()
+	if _, err := fn(DefaultOption(), "bad"); err != fail {
+		t.Fatalf("fn(\"bad\") error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "level=ERROR") || !strings.Contains(buf.String(), "error=fail") {
+		t.Fatalf("log = %q", buf.String())
+	}
+	if want := []string{"outer", "inner", "outer", "inner"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("middleware order = %q, want %q", order, want)
+	}
+}
+
+func TestCachingMiddlewareKey(t *testing.T) {
+	var calls int
+	fn := CachingMiddleware(time.Hour)(func(option Option, pattern string, sub ...string) ([]string, error) {
+		calls++
+		return []string{option.Expr.String()}, nil
+	})
+	call := func(option Option, want string, wantCalls int) {
+		t.Helper()
+		found, err := fn(option, "x", ".")
+		expect(t, found, err, want)
+		if calls != wantCalls {
+			t.Fatalf("calls = %d, want %d", calls, wantCalls)
+		}
+	}
+	// Equal options share an entry, even if their slices are distinct.
+	option := DefaultOption()
+	option.Exclude = []string{"a"}
+	call(option, "fixed", 1)
+	option.Exclude = []string{"a"}
+	call(option, "fixed", 1)
+	option.Expr = expr.Glob
+	call(option, "glob", 2)
+	option.Exclude = []string{"b"}
+	call(option, "glob", 3)
+
+	// Options with function-valued fields are never cached.
+	option.WalkErrHandler = func(string, error) error { return nil }
+	call(option, "glob", 4)
+	call(option, "glob", 5)
+	option.WalkErrHandler = nil
+	option.matcher = func(string, string) (bool, error) { return true, nil }
+	call(option, "glob", 6)
+	option.matcher = nil
+	option.fsys = fstest.MapFS{}
+	call(option, "glob", 7)
+	option.fsys = nil
+	option.TraceHook = func(TraceEvent) {}
+	call(option, "glob", 8)
+	option.TraceHook = nil
+	call(option, "glob", 8)
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package wh
+
+import (
+	"io/fs"
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSkipMountPoints(t *testing.T) {
+	// Directory "mnt" reports a different device ID than its parent.
+	dir := func(dev uint64) *fstest.MapFile {
+		return &fstest.MapFile{Mode: fs.ModeDir | 0o755, Sys: &syscall.Stat_t{Dev: dev}}
+	}
+	fsys := fstest.MapFS{
+		"root":           dir(1),
+		"root/x":         {},
+		"root/local":     dir(1),
+		"root/local/x":   {},
+		"root/mnt":       dir(2),
+		"root/mnt/x":     {},
+		"root/mnt/sub":   dir(2),
+		"root/mnt/sub/x": {},
+	}
+	// The zero Option (other than MaxDepth) descends across devices.
+	option := Option{MaxDepth: 3, Sort: true}
+	found, err := MatchFS(fsys, option, "x", "root")
+	expect(t, found, err, "root/local/x", "root/mnt/sub/x", "root/mnt/x", "root/x")
+
+	option.SkipMountPoints = true
+	found, err = MatchFS(fsys, option, "x", "root")
+	expect(t, found, err, "root/local/x", "root/x")
+}
@@ -0,0 +1,42 @@
+package wh
+
+import "fmt"
+
+// MustMatchFixed is like MatchFixed but panics if an error occurs.
+//
+// It simplifies searching in tests and in the initialization of package-level
+// variables. It should never be used with patterns or directories provided by
+// a user, since any invalid input will cause a panic.
+func MustMatchFixed(option Option, pattern string, sub ...string) []string {
+	return must(MatchFixed, option, pattern, sub...)
+}
+
+// MustMatchGlob is like MatchGlob but panics if an error occurs, including if
+// the given pattern is malformed.
+//
+// It simplifies searching in tests and in the initialization of package-level
+// variables. It should never be used with patterns or directories provided by
+// a user, since any invalid input will cause a panic.
+func MustMatchGlob(option Option, pattern string, sub ...string) []string {
+	return must(MatchGlob, option, pattern, sub...)
+}
+
+// MustMatchRegexp is like MatchRegexp but panics if an error occurs, including
+// if the given pattern cannot be compiled.
+//
+// It simplifies searching in tests and in the initialization of package-level
+// variables. It should never be used with patterns or directories provided by
+// a user, since any invalid input will cause a panic.
+func MustMatchRegexp(option Option, pattern string, sub ...string) []string {
+	return must(MatchRegexp, option, pattern, sub...)
+}
+
+// must returns the result of calling the given MatchFunc fn, or panics with a
+// message containing the pattern and error if fn returns a non-nil error.
+func must(fn MatchFunc, option Option, pattern string, sub ...string) []string {
+	found, err := fn(option, pattern, sub...)
+	if err != nil {
+		panic(fmt.Sprintf("wh: Match(%q): %v", pattern, err))
+	}
+	return found
+}
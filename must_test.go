@@ -0,0 +1,27 @@
+package wh
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMust(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "", "b.txt": ""})
+	option := DefaultOption()
+	if found := MustMatchFixed(option, "a.go", dir); len(found) != 1 {
+		t.Fatalf("MustMatchFixed() = %q", found)
+	}
+	if found := MustMatchGlob(option, "*.txt", dir); len(found) != 1 {
+		t.Fatalf("MustMatchGlob() = %q", found)
+	}
+	if found := MustMatchRegexp(option, `\.(go|txt)$`, dir); len(found) != 2 {
+		t.Fatalf("MustMatchRegexp() = %q", found)
+	}
+	defer func() {
+		if r, _ := recover().(string); !strings.Contains(r, `"("`) {
+			t.Fatalf("MustMatchRegexp(\"(\") panicked with %q", r)
+		}
+	}()
+	MustMatchRegexp(option, "(", dir)
+	t.Fatal("MustMatchRegexp(\"(\") did not panic")
+}
@@ -0,0 +1,38 @@
+package wh
+
+// Negate returns a MatchFunc that reports each file found in the searched
+// directories that is not reported by the given MatchFunc fn.
+//
+// The returned MatchFunc searches each directory twice: once to find all files
+// (according to the same option), and once to find the files reported by fn,
+// which are then removed from the former. Only after both searches complete is
+// the result limited to option.MaxResults files.
+func Negate(fn MatchFunc) MatchFunc {
+	return func(option Option, pattern string, sub ...string) ([]string, error) {
+		// Both searches must be complete to compute the difference.
+		limit := option.MaxResults
+		option.MaxResults = 0
+		all, aerr := MatchGlob(option, "*", sub...)
+		exc, ferr := fn(option, pattern, sub...)
+		if ferr != nil && exc == nil {
+			return nil, ferr
+		}
+		skip := make(map[string]bool, len(exc))
+		for _, s := range exc {
+			skip[s] = true
+		}
+		var found []string
+		for _, s := range all {
+			if !skip[s] {
+				found = append(found, s)
+			}
+		}
+		if limit > 0 && len(found) > limit {
+			found = found[:limit]
+		}
+		if ferr != nil {
+			return found, ferr
+		}
+		return found, aerr
+	}
+}
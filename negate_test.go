@@ -0,0 +1,30 @@
+package wh
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNegate(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "", "b.txt": "", "c.md": "", "d.go": ""})
+	option := DefaultOption()
+	notGlob := Negate(MatchGlob)
+	found, err := notGlob(option, "*.go", dir)
+	expect(t, found, err, join(dir, "b.txt", "c.md")...)
+
+	option.MaxResults = 1
+	found, err = notGlob(option, "*.go", dir)
+	expect(t, found, err, join(dir, "b.txt")...)
+
+	if _, err := notGlob(option, "[", dir); err == nil {
+		t.Fatal("Negate(MatchGlob)(\"[\") = nil error")
+	}
+	fail := errors.New("partial")
+	partial := Negate(func(Option, string, ...string) ([]string, error) {
+		return join(dir, "b.txt"), fail
+	})
+	found, err = partial(DefaultOption(), "", dir)
+	if err != fail || len(found) != 3 {
+		t.Fatalf("Negate(partial) = %q, %v", found, err)
+	}
+}
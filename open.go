@@ -0,0 +1,58 @@
+package wh
+
+import "os"
+
+// ErrNoMatch represents an error in which no file was found matching the given
+// pattern.
+type ErrNoMatch string
+
+// Error returns a descriptive error string for the receiver ErrNoMatch e.
+func (e ErrNoMatch) Error() string {
+	return "no match: " + string(e)
+}
+
+// MatchFirst returns the path of the first file found in the given directories
+// sub whose name matches the given string pattern according to option.Expr.
+//
+// If no file is found, MatchFirst returns ErrNoMatch, even if errors occurred
+// while searching. The errors of a file found are ignored.
+func MatchFirst(option Option, pattern string, sub ...string) (string, error) {
+	// Verify the pattern is valid, so that it is not mistaken for no match.
+	if _, err := option.Expr.Match(option.fold(pattern), ""); err != nil {
+		return "", err
+	}
+	option.MaxResults = 1
+	found, err := MatchResults(option, pattern, sub...)
+	if len(found) > 0 {
+		return found[0].Path, nil
+	}
+	if _, ok := err.(ErrWalkDir); err != nil && !ok {
+		return "", err // Invalid option
+	}
+	return "", ErrNoMatch(pattern)
+}
+
+// Open opens for reading the first file found in the given directories sub
+// whose name matches the given string pattern according to option.Expr.
+// It returns ErrNoMatch if no file is found, or otherwise any error returned
+// by os.Open.
+func Open(option Option, pattern string, sub ...string) (*os.File, error) {
+	p, err := MatchFirst(option, pattern, sub...)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+// OpenFile opens the first file found in the given directories sub whose name
+// matches the given string pattern according to option.Expr, with the given
+// flag and perm used by os.OpenFile.
+// It returns ErrNoMatch if no file is found, or otherwise any error returned
+// by os.OpenFile.
+func OpenFile(option Option, flag int, perm os.FileMode, pattern string, sub ...string) (*os.File, error) {
+	p, err := MatchFirst(option, pattern, sub...)
+	if err != nil {
+		return nil, err
+	}
+	return os.OpenFile(p, flag, perm)
+}
@@ -0,0 +1,58 @@
+package wh
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestOpen(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.txt": "hello", "b.txt": "world"})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+
+	p, err := MatchFirst(option, "*.txt", dir)
+	if err != nil || p != join(dir, "a.txt")[0] {
+		t.Fatalf("MatchFirst() = %q, %v", p, err)
+	}
+	if _, err := MatchFirst(option, "*.go", dir); err != ErrNoMatch("*.go") || err.Error() == "" {
+		t.Fatalf("MatchFirst(no match) error = %v", err)
+	}
+	if _, err := MatchFirst(option, "[", dir); err == nil || errors.As(err, new(ErrNoMatch)) {
+		t.Fatalf("MatchFirst(invalid) error = %v", err)
+	}
+	option.MinSize = -1
+	if _, err := MatchFirst(option, "*.txt", dir); err != ErrInvalidOption("MinSize") {
+		t.Fatalf("MatchFirst(invalid option) error = %v", err)
+	}
+	option.MinSize = 0
+
+	f, err := Open(option, "b*", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(f)
+	f.Close()
+	if err != nil || string(b) != "world" {
+		t.Fatalf("Open() read %q, %v", b, err)
+	}
+	if _, err := Open(option, "z*", dir); err == nil {
+		t.Fatal("Open(no match) = nil error")
+	}
+
+	f, err = OpenFile(option, os.O_WRONLY|os.O_APPEND, 0, "a*", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString("!")
+	f.Close()
+	if b, _ := os.ReadFile(join(dir, "a.txt")[0]); err != nil || string(b) != "hello!" {
+		t.Fatalf("OpenFile() wrote %q, %v", b, err)
+	}
+	if _, err := OpenFile(option, os.O_RDONLY, 0, "z*", dir); err == nil {
+		t.Fatal("OpenFile(no match) = nil error")
+	}
+}
@@ -0,0 +1,46 @@
+package wh
+
+import "github.com/ardnew/wh/expr"
+
+// NewOption returns the Option returned by DefaultOption, modified by each of
+// the given functions opts in the order given, such as:
+//
+//	option := NewOption(WithMaxDepth(3), WithIgnoreCase(true))
+func NewOption(opts ...func(*Option)) Option {
+	option := DefaultOption()
+	for _, fn := range opts {
+		fn(&option)
+	}
+	return option
+}
+
+// WithMaxDepth returns a function setting Option.MaxDepth to n.
+func WithMaxDepth(n int) func(*Option) {
+	return func(o *Option) { o.MaxDepth = n }
+}
+
+// WithFollowSymlinks returns a function setting Option.FollowSymlinks to v.
+func WithFollowSymlinks(v bool) func(*Option) {
+	return func(o *Option) { o.FollowSymlinks = v }
+}
+
+// WithIgnoreCase returns a function setting Option.IgnoreCase to v.
+func WithIgnoreCase(v bool) func(*Option) {
+	return func(o *Option) { o.IgnoreCase = v }
+}
+
+// WithExpr returns a function setting Option.Expr to e.
+func WithExpr(e expr.Expr) func(*Option) {
+	return func(o *Option) { o.Expr = e }
+}
+
+// WithWorkingDir returns a function setting Option.WorkingDir to dir.
+func WithWorkingDir(dir string) func(*Option) {
+	return func(o *Option) { o.WorkingDir = dir }
+}
+
+// WithMaxFollow returns a function setting Option.MaxFollow to n
+// (-1 = unlimited).
+func WithMaxFollow(n int) func(*Option) {
+	return func(o *Option) { o.MaxFollow = n }
+}
@@ -0,0 +1,44 @@
+package wh
+
+import (
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestNewOption(t *testing.T) {
+	option := NewOption(
+		WithMaxDepth(3),
+		WithFollowSymlinks(true),
+		WithIgnoreCase(true),
+		WithExpr(expr.Regexp),
+		WithWorkingDir("/tmp"),
+		WithMaxFollow(-1),
+	)
+	want := DefaultOption()
+	want.MaxDepth = 3
+	want.FollowSymlinks = true
+	want.IgnoreCase = true
+	want.Expr = expr.Regexp
+	want.WorkingDir = "/tmp"
+	want.MaxFollow = -1
+	if option.MaxDepth != want.MaxDepth || option.FollowSymlinks != want.FollowSymlinks ||
+		option.IgnoreCase != want.IgnoreCase || option.Expr != want.Expr ||
+		option.WorkingDir != want.WorkingDir || option.MaxFollow != want.MaxFollow {
+		t.Fatalf("NewOption() = %+v, want %+v", option, want)
+	}
+	if option := NewOption(); option.MaxDepth != want.MaxDepth-2 || option.Expr != expr.Fixed {
+		t.Fatalf("NewOption() = %+v, want DefaultOption()", option)
+	}
+}
+
+func TestWorkingDir(t *testing.T) {
+	dir := makeTree(t, map[string]string{"sub/a.go": ""})
+	option := NewOption(WithWorkingDir(dir), WithExpr(expr.Glob))
+	option.AbsolutePaths = true
+	found, err := MatchGlob(option, "*.go", "sub")
+	expect(t, found, err, join(dir, "sub/a.go")...)
+	option.RelativeTo = "sub"
+	found, err = MatchGlob(option, "*.go", "sub")
+	expect(t, found, err, "a.go")
+}
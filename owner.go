@@ -0,0 +1,83 @@
+package wh
+
+import (
+	"io/fs"
+	"os/user"
+	"strconv"
+)
+
+// ErrUnsupportedOption represents an error in which the named Option field was
+// given a value that is not supported on the current platform.
+type ErrUnsupportedOption string
+
+// Error returns a descriptive error string for the receiver
+// ErrUnsupportedOption e.
+func (e ErrUnsupportedOption) Error() string {
+	return "option not supported on this platform: " + string(e)
+}
+
+// filterOwner reports whether files are filtered by their owner according to
+// option.FilterOwner, option.OwnerUID, and option.OwnerGID.
+func (option Option) filterOwner() bool {
+	return option.FilterOwner && (option.OwnerUID >= 0 || option.OwnerGID >= 0)
+}
+
+// WithOwnerName returns a function that sets the OwnerUID of an Option to the
+// user ID of the user with the given user name, and sets its FilterOwner.
+// The function returns an error if the user does not exist or if its user ID
+// is not numeric (e.g., a Windows SID).
+func WithOwnerName(name string) func(*Option) error {
+	return func(option *Option) error {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return err
+		}
+		uid, err := strconv.Atoi(u.Uid)
+		if err != nil {
+			return err
+		}
+		option.OwnerUID = uid
+		option.FilterOwner = true
+		return nil
+	}
+}
+
+// WithGroupName returns a function that sets the OwnerGID of an Option to the
+// group ID of the group with the given group name, and sets its FilterOwner.
+// The function returns an error if the group does not exist or if its group ID
+// is not numeric (e.g., a Windows SID).
+func WithGroupName(name string) func(*Option) error {
+	return func(option *Option) error {
+		g, err := user.LookupGroup(name)
+		if err != nil {
+			return err
+		}
+		gid, err := strconv.Atoi(g.Gid)
+		if err != nil {
+			return err
+		}
+		option.OwnerGID = gid
+		option.FilterOwner = true
+		return nil
+	}
+}
+
+// owned reports whether the given file d is owned by the user and group
+// specified by option.OwnerUID and option.OwnerGID, respectively, or true if
+// option.FilterOwner is false. Files whose ownership cannot be determined are
+// never owned.
+func (option Option) owned(d fs.DirEntry) bool {
+	if !option.filterOwner() {
+		return true
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	uid, gid, ok := owner(info)
+	if !ok {
+		return false
+	}
+	return (option.OwnerUID < 0 || option.OwnerUID == uid) &&
+		(option.OwnerGID < 0 || option.OwnerGID == gid)
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package wh
+
+import "io/fs"
+
+// ownerSupported reports whether files may be filtered by their owner on this
+// platform, which they cannot. File ownership on Windows is defined by security
+// descriptors (ACLs) identified by SIDs, which have no numeric user or group
+// ID to compare with Option.OwnerUID and Option.OwnerGID.
+const ownerSupported = false
+
+// owner returns the user and group IDs of the owner of the file described by
+// info, and whether or not the IDs could be determined, which they cannot on
+// this platform.
+func owner(info fs.FileInfo) (uid, gid int, ok bool) { return 0, 0, false }
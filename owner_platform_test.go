@@ -0,0 +1,23 @@
+package wh
+
+import "testing"
+
+func TestOwnerSupported(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a": ""})
+	option := DefaultOption()
+	option.FilterOwner = true
+	found, err := Match(option, "a", dir)
+	expect(t, found, err, join(dir, "a")...)
+
+	option.OwnerUID = 0
+	var want error
+	if !ownerSupported {
+		want = ErrUnsupportedOption("FilterOwner")
+	}
+	if err := option.Validate(); err != want {
+		t.Fatalf("Validate() = %v, want %v", err, want)
+	}
+	if _, err := Match(option, "a", dir); err != want {
+		t.Fatalf("Match() error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,85 @@
+//go:build unix
+
+package wh
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+// fileOwner returns the user and group IDs of the owner of the named file.
+func fileOwner(t *testing.T, name string) (uid, gid int) {
+	t.Helper()
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := info.Sys().(*syscall.Stat_t)
+	return int(st.Uid), int(st.Gid)
+}
+
+func TestOwnerZeroValue(t *testing.T) {
+	dir := makeTree(t, map[string]string{"owned.txt": ""})
+	name := filepath.Join(dir, "owned.txt")
+	if os.Getuid() == 0 {
+		// Ensure the file is not owned by uid 0 and gid 0.
+		if err := os.Chown(name, 1000, 1000); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if uid, gid := fileOwner(t, name); uid == 0 && gid == 0 {
+		t.Skip("file is owned by uid 0 and gid 0")
+	}
+	found, err := MatchFixed(Option{MaxDepth: 1}, "owned.txt", dir)
+	expect(t, found, err, name)
+}
+
+func TestOwnerFilter(t *testing.T) {
+	dir := makeTree(t, map[string]string{"owned.txt": ""})
+	name := filepath.Join(dir, "owned.txt")
+	uid, gid := fileOwner(t, name)
+	for _, tt := range []struct {
+		uid, gid int
+		found    bool
+	}{
+		{-1, -1, true},
+		{uid, -1, true},
+		{-1, gid, true},
+		{uid, gid, true},
+		{uid + 1, -1, false},
+		{-1, gid + 1, false},
+		{uid, gid + 1, false},
+	} {
+		option := DefaultOption()
+		option.FilterOwner = true
+		option.OwnerUID, option.OwnerGID = tt.uid, tt.gid
+		found, err := MatchFixed(option, "owned.txt", dir)
+		if tt.found {
+			expect(t, found, err, name)
+		} else {
+			expect(t, found, err)
+		}
+	}
+}
+
+func TestWithOwnerName(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Skip(err)
+	}
+	option := DefaultOption()
+	if err := WithOwnerName(u.Username)(&option); err != nil {
+		t.Fatal(err)
+	}
+	if !option.FilterOwner || strconv.Itoa(option.OwnerUID) != u.Uid {
+		t.Fatalf("FilterOwner = %t, OwnerUID = %d, want true, %s",
+			option.FilterOwner, option.OwnerUID, u.Uid)
+	}
+	if err := WithOwnerName("no such user name")(&option); err == nil {
+		t.Fatal("expected error for unknown user")
+	}
+}
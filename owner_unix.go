@@ -0,0 +1,21 @@
+//go:build unix
+
+package wh
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// ownerSupported reports whether files may be filtered by their owner on this
+// platform.
+const ownerSupported = true
+
+// owner returns the user and group IDs of the owner of the file described by
+// info, and whether or not the IDs could be determined.
+func owner(info fs.FileInfo) (uid, gid int, ok bool) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(st.Uid), int(st.Gid), true
+	}
+	return 0, 0, false
+}
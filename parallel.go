@@ -0,0 +1,128 @@
+package wh
+
+import (
+	"context"
+	"sync"
+)
+
+// matchParallel searches the given directories sub concurrently using up to
+// option.Parallel goroutines, calling visit with the Result of each file whose
+// name matches the given string pattern according to option.Expr.
+//
+// The given visitFunc visit is only ever called from the calling goroutine.
+// If option.PreserveOrder is true, the files found in each directory are
+// visited only after those found in all preceding directories of sub, as if
+// searched sequentially. Otherwise, files are visited as soon as they are
+// found, in any order.
+//
+// Searching stops as soon as visit returns an error. The error of each
+// directory, if any, is returned in the order of sub.
+func (option Option) matchParallel(ctx context.Context, pattern string, sub []string, visit visitFunc) ErrWalkDir {
+
+	type found struct {
+		slot int    // Index in sub of the directory searched
+		r    Result // File found, unless done
+		err  error  // Error searching the directory, if done
+		done bool   // Directory search is complete
+	}
+
+	pctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	root := make([]string, len(sub))
+	for i, p := range sub {
+		root[i] = option.root(p)
+	}
+
+	// Each worker sends every file found and the completion of each directory
+	// on out, which is always drained by the calling goroutine below.
+	jobs := make(chan int)
+	out := make(chan found, option.Parallel)
+	var wg sync.WaitGroup
+	for w := 0; w < option.Parallel && w < len(sub); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				err := option.matchRoot(pctx, pattern, root[i], func(r Result) error {
+					out <- found{slot: i, r: r}
+					return nil
+				})
+				out <- found{slot: i, err: err, done: true}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range sub {
+			if pctx.Err() != nil || option.exceedsMaxDirs() {
+				return // Do not search remaining directories.
+			}
+			select {
+			case jobs <- i:
+			case <-pctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	var verr error
+	vslot := 0 // Index of the directory in which visit failed
+	emit := func(slot int, r Result) {
+		if verr == nil {
+			if verr = visit(r); verr != nil {
+				vslot = slot
+				cancel() // Stop all processing; the caller has failed.
+			}
+		}
+	}
+
+	errs := make([]error, len(sub))
+	pending := make([][]Result, len(sub)) // Files found but not yet visited
+	done := make([]bool, len(sub))
+	next := 0 // Index of the first directory not yet completely visited
+	for f := range out {
+		switch {
+		case f.done:
+			errs[f.slot], done[f.slot] = f.err, true
+			for option.PreserveOrder && next < len(sub) && done[next] {
+				next++
+				if next < len(sub) {
+					for _, r := range pending[next] {
+						emit(next, r)
+					}
+					pending[next] = nil
+				}
+			}
+		case option.PreserveOrder && f.slot != next:
+			pending[f.slot] = append(pending[f.slot], f.r)
+		default:
+			emit(f.slot, f.r)
+		}
+	}
+	// Visit the files found in directories following any that were not searched.
+	for i, p := range pending {
+		for _, r := range p {
+			emit(i, r)
+		}
+	}
+
+	serr := make(ErrWalkDir, 0, len(sub))
+	for i, err := range errs {
+		if verr != nil {
+			if i == vslot {
+				err = verr
+			} else if err == context.Canceled && ctx.Err() == nil {
+				continue // Remove the errors caused by stopping the search.
+			}
+		}
+		if err != nil {
+			serr = append(serr, errWalkDir{dir: root[i], err: err})
+		}
+	}
+	return serr
+}
@@ -0,0 +1,88 @@
+package wh
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUndefinedEnv represents an error in which an environment variable is not
+// defined.
+type ErrUndefinedEnv string
+
+// Error returns a descriptive error string for the receiver ErrUndefinedEnv e.
+func (e ErrUndefinedEnv) Error() string {
+	return "environment variable not defined: " + string(e)
+}
+
+// PathEnv is a list of directories, such as those in the PATH environment
+// variable.
+//
+// The methods of PathEnv never modify the receiver; those that change the list
+// return a modified copy.
+type PathEnv []string
+
+// FromEnv returns the PathEnv of directories in the environment variable with
+// the given name, or ErrUndefinedEnv if the variable is not defined.
+func FromEnv(varName string) (PathEnv, error) {
+	p, ok := os.LookupEnv(varName)
+	if !ok {
+		return nil, ErrUndefinedEnv(varName)
+	}
+	return PathEnv(filepath.SplitList(p)), nil
+}
+
+// Contains reports whether the receiver PathEnv p contains the given directory,
+// comparing each after lexical cleaning with filepath.Clean. An empty element
+// is the same as ".", according to POSIX convention.
+func (p PathEnv) Contains(dir string) bool {
+	dir = filepath.Clean(dir)
+	for _, d := range p {
+		if filepath.Clean(d) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// Add returns a copy of the receiver PathEnv p with the given directory
+// appended, unless p already contains it.
+func (p PathEnv) Add(dir string) PathEnv {
+	if p.Contains(dir) {
+		return append(PathEnv{}, p...)
+	}
+	return append(append(make(PathEnv, 0, len(p)+1), p...), dir)
+}
+
+// Remove returns a copy of the receiver PathEnv p with every occurrence of the
+// given directory removed.
+func (p PathEnv) Remove(dir string) PathEnv {
+	dir = filepath.Clean(dir)
+	q := PathEnv{}
+	for _, d := range p {
+		if filepath.Clean(d) != dir {
+			q = append(q, d)
+		}
+	}
+	return q
+}
+
+// Dirs returns a copy of the directories in the receiver PathEnv p with any
+// empty element replaced by "." (the current working directory), according to
+// POSIX convention.
+func (p PathEnv) Dirs() []string {
+	dirs := make([]string, len(p))
+	for i, d := range p {
+		if d == "" {
+			d = "."
+		}
+		dirs[i] = d
+	}
+	return dirs
+}
+
+// String returns the directories in the receiver PathEnv p joined by the OS
+// path list separator (':' on POSIX, ';' on Windows), as in PATH.
+func (p PathEnv) String() string {
+	return strings.Join(p, string(os.PathListSeparator))
+}
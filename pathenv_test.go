@@ -0,0 +1,50 @@
+package wh
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPathEnvDirs(t *testing.T) {
+	elem := []string{"", "/usr/bin", "", "/bin", ""}
+	t.Setenv("WH_TEST_PATH", strings.Join(elem, string(os.PathListSeparator)))
+	p, err := FromEnv("WH_TEST_PATH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".", "/usr/bin", ".", "/bin", "."}
+	if dirs := p.Dirs(); !reflect.DeepEqual(dirs, want) {
+		t.Fatalf("Dirs() = %q, want %q", dirs, want)
+	}
+	for _, d := range want {
+		if err := ValidPath(d); err != nil {
+			t.Fatalf("ValidPath(%q) = %v", d, err)
+		}
+	}
+	if err := ValidPath(""); err == nil {
+		t.Fatal("ValidPath(\"\") = nil, want error")
+	}
+}
+
+func TestPathEnv(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	p := PathEnv{"/bin", "/usr/bin/"}
+	if !p.Contains("/usr/bin") || p.Contains("/sbin") {
+		t.Fatalf("%q.Contains() is incorrect", p)
+	}
+	if q := p.Add("/usr/bin"); !reflect.DeepEqual(q, p) || &q[0] == &p[0] {
+		t.Fatalf("Add(existing) = %q, want copy of %q", q, p)
+	}
+	q := p.Add("/sbin")
+	if q.String() != "/bin"+sep+"/usr/bin/"+sep+"/sbin" || len(p) != 2 {
+		t.Fatalf("Add(\"/sbin\") = %q, receiver %q", q, p)
+	}
+	if r := q.Add("/bin").Remove("/usr/bin"); !reflect.DeepEqual(r, PathEnv{"/bin", "/sbin"}) {
+		t.Fatalf("Remove(\"/usr/bin\") = %q", r)
+	}
+	if _, err := FromEnv("WH_TEST_UNDEFINED"); err != ErrUndefinedEnv("WH_TEST_UNDEFINED") {
+		t.Fatalf("FromEnv(undefined) error = %v", err)
+	}
+}
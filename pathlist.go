@@ -0,0 +1,61 @@
+package wh
+
+import "path/filepath"
+
+// PathList is a list of file paths, such as the files found by Match, that may
+// be combined with other lists as sets.
+//
+// Paths are compared after lexical cleaning with filepath.Clean, but retain
+// their original form in each returned list. The methods of PathList never
+// modify the receiver, and each returned list contains no two equal paths, in
+// the order they first appear in the receiver followed by the argument.
+type PathList []string
+
+// set returns the set of each cleaned path in the receiver PathList p.
+func (p PathList) set() map[string]struct{} {
+	s := make(map[string]struct{}, len(p))
+	for _, e := range p {
+		s[filepath.Clean(e)] = struct{}{}
+	}
+	return s
+}
+
+// filter returns each path in the receiver PathList p for which keep returns
+// true, given the cleaned path, omitting those equal to any preceding path.
+func (p PathList) filter(keep func(clean string) bool) PathList {
+	q := PathList{}
+	seen := map[string]struct{}{}
+	for _, e := range p {
+		c := filepath.Clean(e)
+		if _, ok := seen[c]; ok || !keep(c) {
+			continue
+		}
+		seen[c] = struct{}{}
+		q = append(q, e)
+	}
+	return q
+}
+
+// Union returns each path in either the receiver PathList p or other.
+func (p PathList) Union(other PathList) PathList {
+	all := append(append(make(PathList, 0, len(p)+len(other)), p...), other...)
+	return all.filter(func(string) bool { return true })
+}
+
+// Intersect returns each path in both the receiver PathList p and other.
+func (p PathList) Intersect(other PathList) PathList {
+	s := other.set()
+	return p.filter(func(c string) bool {
+		_, ok := s[c]
+		return ok
+	})
+}
+
+// Difference returns each path in the receiver PathList p but not in other.
+func (p PathList) Difference(other PathList) PathList {
+	s := other.set()
+	return p.filter(func(c string) bool {
+		_, ok := s[c]
+		return !ok
+	})
+}
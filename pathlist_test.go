@@ -0,0 +1,24 @@
+package wh
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathList(t *testing.T) {
+	p := PathList{"a", "./b", "c/", "a"}
+	q := PathList{"b", "d", "c"}
+	for _, tc := range []struct {
+		name      string
+		got, want PathList
+	}{
+		{"Union", p.Union(q), PathList{"a", "./b", "c/", "d"}},
+		{"Intersect", p.Intersect(q), PathList{"./b", "c/"}},
+		{"Difference", p.Difference(q), PathList{"a"}},
+		{"Difference", q.Difference(q), PathList{}},
+	} {
+		if !reflect.DeepEqual(tc.got, tc.want) {
+			t.Errorf("%s() = %q, want %q", tc.name, tc.got, tc.want)
+		}
+	}
+}
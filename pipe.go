@@ -0,0 +1,47 @@
+package wh
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// ErrNoCommand represents an error in which an empty command was given.
+type ErrNoCommand bool
+
+// Error returns a descriptive error string for the receiver ErrNoCommand e.
+func (ErrNoCommand) Error() string {
+	return "no command given"
+}
+
+// MatchPipe returns the result of calling MatchPipeWithContext with a context
+// that is never canceled.
+func MatchPipe(option Option, pattern string, cmd []string, sub ...string) ([]string, error) {
+	return MatchPipeWithContext(context.Background(), option, pattern, cmd, sub...)
+}
+
+// MatchPipeWithContext returns each file found in each of the given directories
+// sub whose name matches the given string pattern according to option.Expr, and
+// for which the given command exits with status 0. The command is run once for
+// each such file, with cmd[0] as the program, and cmd[1:] followed by the path
+// of the file as its arguments (as with "find -exec cmd {} ;"). The standard
+// input, output, and error of the command are discarded.
+//
+// The command is killed and the search stopped as soon as the given context ctx
+// is done. An ErrNoCommand is returned if cmd is empty, and the search is
+// stopped if the command cannot be started (e.g., it is not found).
+func MatchPipeWithContext(ctx context.Context, option Option, pattern string, cmd []string, sub ...string) ([]string, error) {
+	if len(cmd) == 0 {
+		return nil, ErrNoCommand(true)
+	}
+	option.predicate = func(r Result) (bool, error) {
+		arg := append(append([]string{}, cmd[1:]...), r.Chain.Head().Path())
+		err := exec.CommandContext(ctx, cmd[0], arg...).Run()
+		var exit *exec.ExitError
+		if errors.As(err, &exit) && ctx.Err() == nil {
+			return false, nil // Command ran, but failed.
+		}
+		return err == nil, err
+	}
+	return MatchWithContext(ctx, option, option.fold(pattern), sub...)
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package wh
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestMatchPipe(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.txt": "data", "b.txt": "", "c.md": "data"})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	found, err := MatchPipe(option, "*.txt", []string{"test", "-s"}, dir)
+	expect(t, found, err, join(dir, "a.txt")...)
+
+	if _, err := MatchPipe(option, "*", nil, dir); err != ErrNoCommand(true) {
+		t.Fatalf("MatchPipe(no command) error = %v", err)
+	}
+	if _, err := MatchPipe(option, "*", []string{"wh-test-no-such-command"}, dir); err == nil {
+		t.Fatal("MatchPipe(missing command) = nil error")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := MatchPipeWithContext(ctx, option, "*", []string{"true"}, dir); !errors.Is(err, context.Canceled) {
+		t.Fatalf("MatchPipeWithContext(canceled) error = %v", err)
+	}
+}
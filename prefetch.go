@@ -0,0 +1,87 @@
+package wh
+
+import (
+	"io/fs"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// prefetchDir contains the entries of a directory read in advance.
+type prefetchDir struct {
+	done chan struct{} // Closed once ents and err are set
+	ents []fs.DirEntry
+	err  error
+}
+
+// prefetcher reads directories concurrently in advance of walking them, with at
+// most runtime.GOMAXPROCS(0) directories read at any time.
+type prefetcher struct {
+	sem chan struct{}
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	dir map[string]*prefetchDir
+}
+
+// newPrefetch returns a new prefetcher.
+func newPrefetch() *prefetcher {
+	return &prefetcher{
+		sem: make(chan struct{}, runtime.GOMAXPROCS(0)),
+		dir: map[string]*prefetchDir{},
+	}
+}
+
+// prefetch starts reading the entries of each subdirectory in the given entries
+// ents of the named directory in fsys, if option.ParallelReadDir is true.
+// Subdirectories that would exceed option.MaxDepth are not read.
+func (option Option) prefetch(fsys fs.FS, name string, ents []fs.DirEntry) {
+	if option.pre == nil {
+		return
+	}
+	// Depth of each entry of name, computed the same as in match.
+	depth := option.fromDepth + 1
+	if name != "." {
+		depth += strings.Count(name, "/") + 1
+	}
+	if depth >= option.MaxDepth {
+		return
+	}
+	for _, e := range ents {
+		if e.IsDir() {
+			option.pre.start(option, fsys, path.Join(name, e.Name()))
+		}
+	}
+}
+
+// start reads the entries of the named directory in fsys in a new goroutine.
+func (p *prefetcher) start(option Option, fsys fs.FS, name string) {
+	d := &prefetchDir{done: make(chan struct{})}
+	p.mu.Lock()
+	p.dir[name] = d
+	p.mu.Unlock()
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(d.done)
+		p.sem <- struct{}{}
+		defer func() { <-p.sem }()
+		d.ents, d.err = option.read(fsys, name)
+	}()
+}
+
+// get returns the named directory, once its entries have been read, and true if
+// the directory was read in advance. Otherwise, get returns nil and false.
+func (p *prefetcher) get(name string) (*prefetchDir, bool) {
+	p.mu.Lock()
+	d, ok := p.dir[name]
+	delete(p.dir, name)
+	p.mu.Unlock()
+	if ok {
+		<-d.done
+	}
+	return d, ok
+}
+
+// wait waits for all directories being read to complete.
+func (p *prefetcher) wait() { p.wg.Wait() }
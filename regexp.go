@@ -0,0 +1,141 @@
+package wh
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// MatchByRegex returns each file found in each of the given directories sub
+// whose name matches the given compiled regular expression re.
+//
+// Unlike MatchRegexp, re is used directly without consulting the expression
+// cache, and its own flags determine case sensitivity instead of
+// option.IgnoreCase. The MatchedPattern of each Result is re.String().
+func MatchByRegex(option Option, re *regexp.Regexp, sub ...string) ([]string, error) {
+	option.Expr = expr.Regexp
+	option.matcher = func(_, name string) (bool, error) {
+		return re.MatchString(name), nil
+	}
+	option.IgnoreCase = false
+	var found []string
+	err := match(context.Background(), option, re.String(), sub, func(r Result) error {
+		found = append(found, r.String())
+		return nil
+	})
+	return found, err
+}
+
+// ErrPattern represents an error in which the given Pattern is not a valid
+// regular expression.
+type ErrPattern struct {
+	Pattern string
+	Err     error
+}
+
+// Error returns a descriptive error string for the receiver ErrPattern e.
+func (e ErrPattern) Error() string {
+	return "invalid pattern " + strconv.Quote(e.Pattern) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error describing why the receiver ErrPattern e is invalid.
+func (e ErrPattern) Unwrap() error { return e.Err }
+
+// MatchRegexpPositive returns each file found in each of the given directories
+// sub whose name matches the regular expression mustMatch but not the regular
+// expression mustNotMatch, as with MatchRegexp.
+//
+// Go's regexp package does not support lookahead assertions, such as the
+// pattern "^(?!.*_test)(.*)\.go$". This is not lookahead: each file name is
+// instead tested against both expressions separately, so the equivalent is
+// mustMatch "\.go$" and mustNotMatch "_test". An ErrPattern is returned before
+// searching if either expression is invalid.
+func MatchRegexpPositive(option Option, mustMatch string, mustNotMatch string, sub ...string) ([]string, error) {
+	prefix := ""
+	if option.IgnoreCase {
+		prefix = "(?i)"
+	}
+	if _, err := regexp.Compile(prefix + mustMatch); err != nil {
+		return nil, ErrPattern{Pattern: mustMatch, Err: err}
+	}
+	not, err := regexp.Compile(prefix + mustNotMatch)
+	if err != nil {
+		return nil, ErrPattern{Pattern: mustNotMatch, Err: err}
+	}
+	option.predicate = func(r Result) (bool, error) {
+		return !not.MatchString(r.Name()), nil
+	}
+	return MatchRegexp(option, mustMatch, sub...)
+}
+
+// NamedCapture associates a file matching a regular expression with the text
+// of each named capturing group in the expression.
+type NamedCapture struct {
+	Path   string            // File matching the regular expression
+	Groups map[string]string // Text matched by each named capturing group
+}
+
+// MatchRegexpNamed returns each file whose name matches the given string
+// pattern according to regexp.Regexp semantics, along with the text matched by
+// each named capturing group (e.g., "(?P<version>[0-9.]+)") in the pattern.
+//
+// Named groups that do not participate in the match are mapped to the empty
+// string. If the pattern contains no named groups, Groups is empty.
+func MatchRegexpNamed(option Option, pattern string, sub ...string) ([]NamedCapture, error) {
+	option.Expr = expr.Regexp
+	re, err := regexp.Compile(option.fold(pattern))
+	if err != nil {
+		return nil, err
+	}
+	option.matcher = func(_, name string) (bool, error) {
+		return re.MatchString(name), nil
+	}
+	option.IgnoreCase = false // Already folded into re
+	var found []NamedCapture
+	err = match(context.Background(), option, pattern, sub, func(r Result) error {
+		nc := NamedCapture{Path: r.String(), Groups: map[string]string{}}
+		m := re.FindStringSubmatch(r.Name())
+		for i, name := range re.SubexpNames() {
+			if name != "" && i < len(m) {
+				nc.Groups[name] = m[i]
+			}
+		}
+		found = append(found, nc)
+		return nil
+	})
+	return found, err
+}
+
+// MatchAllResult associates a file matching a regular expression with the byte
+// index range of each non-overlapping match of the expression in its name.
+type MatchAllResult struct {
+	Path    string  // File matching the regular expression
+	Matches [][]int // Start and end index of each match in the file's base name
+}
+
+// MatchRegexpFindAll returns each file whose name matches the given string
+// pattern according to regexp.Regexp semantics, along with the byte index range
+// of every successive non-overlapping match of the pattern in its base name, as
+// returned by (*regexp.Regexp).FindAllStringIndex.
+func MatchRegexpFindAll(option Option, pattern string, sub ...string) ([]MatchAllResult, error) {
+	option.Expr = expr.Regexp
+	re, err := regexp.Compile(option.fold(pattern))
+	if err != nil {
+		return nil, err
+	}
+	option.matcher = func(_, name string) (bool, error) {
+		return re.MatchString(name), nil
+	}
+	option.IgnoreCase = false // Already folded into re
+	var found []MatchAllResult
+	err = match(context.Background(), option, pattern, sub, func(r Result) error {
+		found = append(found, MatchAllResult{
+			Path:    r.String(),
+			Matches: re.FindAllStringIndex(r.Name(), -1),
+		})
+		return nil
+	})
+	return found, err
+}
@@ -0,0 +1,61 @@
+package wh
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestMatchRegexp(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"app-v1.2.txt": "", "App-v10.0.txt": "", "main.go": "", "main_test.go": "",
+	})
+	option := DefaultOption()
+
+	found, err := MatchByRegex(option, regexp.MustCompile(`(?i)^app`), dir)
+	expect(t, found, err, join(dir, "App-v10.0.txt", "app-v1.2.txt")...)
+
+	found, err = MatchRegexpPositive(option, `\.go$`, `_test`, dir)
+	expect(t, found, err, join(dir, "main.go")...)
+	option.IgnoreCase = true
+	found, err = MatchRegexpPositive(option, `^APP`, `V10`, dir)
+	expect(t, found, err, join(dir, "app-v1.2.txt")...)
+	for _, p := range [][2]string{{"(", "x"}, {"x", "["}} {
+		var perr ErrPattern
+		if _, err := MatchRegexpPositive(option, p[0], p[1], dir); !errors.As(err, &perr) ||
+			perr.Unwrap() == nil || perr.Error() == "" {
+			t.Errorf("MatchRegexpPositive(%q, %q) error = %v, want ErrPattern", p[0], p[1], err)
+		}
+	}
+
+	named, err := MatchRegexpNamed(option, `^APP-v(?P<major>\d+)\.(?P<minor>\d+)(?P<patch>\.\d+)?`, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []NamedCapture{
+		{join(dir, "App-v10.0.txt")[0], map[string]string{"major": "10", "minor": "0", "patch": ""}},
+		{join(dir, "app-v1.2.txt")[0], map[string]string{"major": "1", "minor": "2", "patch": ""}},
+	}
+	if !reflect.DeepEqual(named, want) {
+		t.Fatalf("MatchRegexpNamed() = %v, want %v", named, want)
+	}
+	if _, err := MatchRegexpNamed(option, "(", dir); err == nil {
+		t.Fatal("MatchRegexpNamed(\"(\") = nil error")
+	}
+
+	all, err := MatchRegexpFindAll(option, `[0-9]+`, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantAll := []MatchAllResult{
+		{join(dir, "App-v10.0.txt")[0], [][]int{{5, 7}, {8, 9}}},
+		{join(dir, "app-v1.2.txt")[0], [][]int{{5, 6}, {7, 8}}},
+	}
+	if !reflect.DeepEqual(all, wantAll) {
+		t.Fatalf("MatchRegexpFindAll() = %v, want %v", all, wantAll)
+	}
+	if _, err := MatchRegexpFindAll(option, "(", dir); err == nil {
+		t.Fatal("MatchRegexpFindAll(\"(\") = nil error")
+	}
+}
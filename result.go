@@ -0,0 +1,124 @@
+package wh
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"path"
+)
+
+// Result describes a single file found matching a pattern.
+type Result struct {
+	Path  string // Path of the matching file, as found in its search directory
+	Root  string // Search directory in which the file was found
+	Depth int    // Number of path components between Root and Path
+	Chain Chain  // Path followed by each symlink it was dereferenced through
+
+	MatchedPattern string // Pattern the file name matched, if known
+	Module         string // Go module root containing Path, if Option.GoModuleAware
+	ent            fs.DirEntry
+	rel            func(string) string // Converts paths relative to RelativeTo
+}
+
+// MatchResults returns the Result of each file found in each of the given
+// directories sub whose name matches the given string pattern according to
+// option.Expr, sorted by option.SortBy if set.
+func MatchResults(option Option, pattern string, sub ...string) ([]Result, error) {
+	return matchResults(context.Background(), option, option.fold(pattern), sub)
+}
+
+// matchResults returns the Result of each file found by match, sorted by
+// option.SortBy if set.
+func matchResults(ctx context.Context, option Option, pattern string, sub []string) ([]Result, error) {
+	var found []Result
+	err := match(ctx, option, pattern, sub, func(r Result) error {
+		found = append(found, r)
+		return nil
+	})
+	if serr := sortResults(found, option.SortBy); serr != nil {
+		return found, serr
+	}
+	return found, err
+}
+
+// MatchAnyResults returns the Result of each file found in each of the given
+// directories sub whose name matches any of the given string patterns according
+// to option.Expr, searching each directory only once as with MatchAny, sorted
+// by option.SortBy if set.
+func MatchAnyResults(option Option, pattern []string, sub ...string) ([]Result, error) {
+	var found []Result
+	err := matchAny(context.Background(), option, pattern, sub, func(r Result) error {
+		found = append(found, r)
+		return nil
+	})
+	if serr := sortResults(found, option.SortBy); serr != nil {
+		return found, serr
+	}
+	return found, err
+}
+
+// MatchStat returns the fs.FileInfo of each file found in each of the given
+// directories sub whose name matches the given string pattern according to
+// option.Expr, in the same order as Match, as returned by Result.Info.
+//
+// The attributes of each file are read from its directory entry, which avoids
+// reading them again if they were already read to filter the file. Files whose
+// attributes cannot be read (e.g., removed while searching) are omitted.
+func MatchStat(option Option, pattern string, sub ...string) ([]fs.FileInfo, error) {
+	var found []fs.FileInfo
+	err := match(context.Background(), option, option.fold(pattern), sub, func(r Result) error {
+		if info, ierr := r.Info(); ierr == nil {
+			found = append(found, info)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// String returns the string representation of the receiver Result r, which is
+// the same as that of r.Chain, but with each path relative to the
+// Option.RelativeTo with which it was found, if set.
+func (r Result) String() string { return r.StringWith(UnicodeFormatter{}) }
+
+// StringWith returns the representation of the receiver Result r using the
+// given formatter f, which is the same as that of r.Chain, but with each path
+// relative to the Option.RelativeTo with which it was found, if set.
+func (r Result) StringWith(f ChainFormatter) string {
+	if r.rel == nil {
+		return r.Chain.StringWith(f)
+	}
+	link := make([]Link, len(r.Chain))
+	for i, l := range r.Chain {
+		link[i] = *l
+		link[i].root, link[i].name = r.rel(l.Path()), ""
+	}
+	return f.Format(link)
+}
+
+// Name returns the base name of the matching file.
+func (r Result) Name() string { return path.Base(r.Path) }
+
+// Dir returns the directory containing the matching file.
+func (r Result) Dir() string { return path.Dir(r.Path) }
+
+// Info returns the fs.FileInfo of the matching file, or of the file it refers
+// to if symlinks were followed.
+func (r Result) Info() (fs.FileInfo, error) {
+	if r.ent == nil {
+		return r.Chain.Tail().Stat()
+	}
+	return r.ent.Info()
+}
+
+// Results is a list of paths of files found matching a pattern.
+type Results []string
+
+// MarshalJSON implements the json.Marshaler interface's MarshalJSON method.
+// The receiver Results r is encoded as a JSON array of strings, which is empty
+// ("[]") rather than null if r is nil.
+func (r Results) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(r))
+}
@@ -0,0 +1,59 @@
+package wh
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestMatchAnyResults(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "12", "b.md": "1", "c.txt": "123"})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.SortBy = "size"
+	res, err := MatchAnyResults(option, []string{"*.go", "*.md", "*.go"}, dir)
+	if err != nil || len(res) != 2 || res[0].Name() != "b.md" || res[1].Name() != "a.go" {
+		t.Fatalf("MatchAnyResults() = %v, %v", res, err)
+	}
+	if res[0].Dir() != dir || res[0].Root != dir || res[0].Depth != 1 {
+		t.Fatalf("Result = %+v", res[0])
+	}
+	option.SortBy = "color"
+	if _, err := MatchAnyResults(option, []string{"*"}, dir); err != ErrInvalidSortKey("color") {
+		t.Fatalf("MatchAnyResults(invalid SortBy) error = %v", err)
+	}
+
+	option.SortBy = ""
+	option.RelativeTo = dir
+	res, err = MatchAnyResults(option, []string{"c.*"}, dir)
+	if err != nil || len(res) != 1 || res[0].String() != "c.txt" ||
+		res[0].StringWith(ArrowFormatter{}) != "c.txt" {
+		t.Fatalf("MatchAnyResults(RelativeTo) = %v, %v", res, err)
+	}
+}
+
+func TestMatchStat(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "12", "b.go": "1", "c.txt": "123"})
+	option := DefaultOption()
+	option.Expr = expr.Suffix
+	info, err := MatchStat(option, ".go", dir)
+	if err != nil || len(info) != 2 || info[0].Name() != "a.go" || info[1].Size() != 1 {
+		t.Fatalf("MatchStat() = %v, %v", info, err)
+	}
+}
+
+func TestResultsJSON(t *testing.T) {
+	for _, tc := range []struct {
+		r    Results
+		want string
+	}{
+		{nil, "[]"},
+		{Results{}, "[]"},
+		{Results{"a", "b"}, `["a","b"]`},
+	} {
+		if b, err := json.Marshal(tc.r); err != nil || string(b) != tc.want {
+			t.Errorf("json.Marshal(%q) = %s, %v, want %s", tc.r, b, err, tc.want)
+		}
+	}
+}
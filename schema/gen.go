@@ -0,0 +1,167 @@
+//go:build ignore
+
+// Command gen writes the JSON Schema of the JSON encoding of wh.Option, as read
+// by wh.LoadConfig, to option.schema.json in this directory.
+//
+// The type of each property is derived from the Option struct by reflection,
+// and its description from the comment of the corresponding struct field.
+// Run "go generate" in the module root directory to regenerate the schema.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ardnew/wh"
+	"github.com/ardnew/wh/expr"
+)
+
+// schemaID is the URI identifying the generated schema.
+const schemaID = "https://raw.githubusercontent.com/ardnew/wh/master/schema/option.schema.json"
+
+// object is a JSON object whose keys are encoded in lexical order.
+type object map[string]any
+
+func main() {
+	typeDoc, fieldDoc := comments("Option")
+	prop := object{
+		"$schema": object{
+			"description": "URI of the JSON Schema of this file",
+			"type":        "string",
+		},
+	}
+	t := reflect.TypeOf(wh.Option{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("json") == "-" {
+			continue
+		}
+		p := schema(f.Type)
+		if d := fieldDoc[f.Name]; d != "" {
+			p["description"] = d
+			// Fields documented with a value having special meaning, such as
+			// "(-1 = any)" or "(0 = unlimited)", accept no value less than it.
+			if strings.Contains(d, "(-1 = ") && p["type"] == "integer" {
+				p["minimum"] = -1
+			} else if strings.Contains(d, "(0 = ") && p["type"] == "integer" {
+				p["minimum"] = 0
+			}
+		}
+		prop[f.Name] = p
+	}
+	out, err := marshal(object{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"$id":                  schemaID,
+		"title":                "wh.Option",
+		"description":          typeDoc,
+		"type":                 "object",
+		"properties":           prop,
+		"additionalProperties": false,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("schema", "option.schema.json"), out, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// schema returns the JSON Schema of the JSON encoding of the given type.
+func schema(t reflect.Type) object {
+	switch t {
+	case reflect.TypeOf(expr.Expr(0)):
+		var name []string
+		for e := expr.Fixed; e <= expr.BraceExpr; e++ {
+			if b, err := e.MarshalText(); err == nil {
+				name = append(name, string(b))
+			}
+		}
+		return object{"type": "string", "enum": name}
+	case reflect.TypeOf(wh.StringSearchAlgo(0)):
+		return object{"type": "integer", "enum": []wh.StringSearchAlgo{
+			wh.AlgoNaive, wh.AlgoKMP, wh.AlgoAhoCorasick,
+		}}
+	case reflect.TypeOf(wh.FileTypeMask(0)):
+		return object{"type": "integer", "minimum": 0,
+			"maximum": wh.TypeRegular | wh.TypeDir | wh.TypeSymlink |
+				wh.TypeDevice | wh.TypeNamedPipe | wh.TypeSocket}
+	case reflect.TypeOf(os.FileMode(0)):
+		// Access rights may be given in any class of permission bits.
+		return object{"type": "integer", "minimum": 0, "maximum": os.ModePerm}
+	case reflect.TypeOf(time.Time{}):
+		return object{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return object{"type": "integer"}
+	case reflect.String:
+		return object{"type": "string"}
+	case reflect.Slice:
+		return object{"type": "array", "items": schema(t.Elem())}
+	}
+	log.Fatalf("unsupported type: %s", t)
+	return nil
+}
+
+// comments returns the doc comment of the type with the given name declared in
+// package wh, and the comment of each of its struct fields keyed by name.
+func comments(typeName string) (string, map[string]string) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var files []*ast.File
+	for _, f := range pkgs["wh"].Files {
+		files = append(files, f)
+	}
+	p, err := doc.NewFromFiles(fset, files, "github.com/ardnew/wh")
+	if err != nil {
+		log.Fatal(err)
+	}
+	field := map[string]string{}
+	for _, t := range p.Types {
+		if t.Name != typeName {
+			continue
+		}
+		st := t.Decl.Specs[0].(*ast.TypeSpec).Type.(*ast.StructType)
+		for _, f := range st.Fields.List {
+			text := f.Doc.Text()
+			if f.Comment != nil {
+				text = f.Comment.Text()
+			}
+			for _, n := range f.Names {
+				field[n.Name] = strings.TrimSpace(text)
+			}
+		}
+		return strings.TrimSpace(t.Doc), field
+	}
+	log.Fatalf("type not found: %s", typeName)
+	return "", nil
+}
+
+// marshal returns the indented JSON encoding of v, without escaping characters
+// special to HTML (e.g., "<") in descriptions.
+func marshal(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(v)
+	return b.Bytes(), err
+}
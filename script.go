@@ -0,0 +1,27 @@
+package wh
+
+import (
+	"bytes"
+	"strings"
+)
+
+// MatchScript returns the result of calling Match with option.ScriptInterpreter
+// set to the given interpreter, so that only scripts whose shebang line (e.g.,
+// "#!/usr/bin/env python3") contains interpreter are reported.
+func MatchScript(option Option, interpreter, pattern string, sub ...string) ([]string, error) {
+	option.ScriptInterpreter = interpreter
+	return Match(option, option.fold(pattern), sub...)
+}
+
+// interpreted reports whether the given head of a file begins with a shebang
+// line containing option.ScriptInterpreter.
+func (option Option) interpreted(head []byte) bool {
+	line, ok := bytes.CutPrefix(head, []byte("#!"))
+	if !ok {
+		return false
+	}
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.Contains(string(line), option.ScriptInterpreter)
+}
@@ -0,0 +1,22 @@
+package wh
+
+import "testing"
+
+func TestMatchScript(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"Build":    "#!/usr/bin/env python3\nprint()\n",
+		"build.sh": "#!/bin/sh\necho\n",
+		"README":   "python3\n",
+	})
+	option := DefaultOption()
+	found, err := MatchScript(option, "python", "Build", dir)
+	expect(t, found, err, join(dir, "Build")...)
+	found, err = MatchScript(option, "python", "README", dir)
+	expect(t, found, err)
+
+	option.IgnoreCase = true
+	found, err = MatchScript(option, "python", "BUILD", dir)
+	expect(t, found, err, join(dir, "Build")...)
+	found, err = MatchScript(option, "sh", "BUILD.SH", dir)
+	expect(t, found, err, join(dir, "build.sh")...)
+}
@@ -0,0 +1,40 @@
+package wh
+
+import "os"
+
+// DefaultSearchDirs returns the list of directories to search when no search
+// directories are otherwise specified.
+//
+// The list contains each directory in the PATH environment variable, with any
+// empty element replaced by "." (the current working directory), according to
+// POSIX convention. If PATH is not defined, the list contains only the current
+// working directory.
+//
+// On Windows, the list additionally contains each directory in the system and
+// user Path variables stored in the registry that is not already present in
+// the PATH environment variable. This includes directories that were added to
+// the registry after the calling process was started.
+func DefaultSearchDirs() []string {
+	var dirs []string
+	if p, err := FromEnv("PATH"); err == nil {
+		dirs = p.Dirs()
+	}
+	seen := map[string]bool{}
+	for _, d := range dirs {
+		seen[d] = true
+	}
+	for _, d := range platformSearchDirs() {
+		if !seen[d] {
+			seen[d] = true
+			dirs = append(dirs, d)
+		}
+	}
+	if len(dirs) == 0 {
+		w, err := os.Getwd()
+		if err != nil {
+			w = "."
+		}
+		dirs = append(dirs, w)
+	}
+	return dirs
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package wh
+
+// platformSearchDirs returns the platform-specific directories appended to the
+// list returned by DefaultSearchDirs, of which there are none on this platform.
+func platformSearchDirs() []string { return nil }
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package wh
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultSearchDirs(t *testing.T) {
+	t.Setenv("PATH", "/bin::/usr/bin")
+	if dirs, want := DefaultSearchDirs(), []string{"/bin", ".", "/usr/bin"}; !reflect.DeepEqual(dirs, want) {
+		t.Fatalf("DefaultSearchDirs() = %q, want %q", dirs, want)
+	}
+	os.Unsetenv("PATH")
+	wd, _ := os.Getwd()
+	if dirs := DefaultSearchDirs(); !reflect.DeepEqual(dirs, []string{wd}) {
+		t.Fatalf("DefaultSearchDirs() without PATH = %q, want %q", dirs, []string{wd})
+	}
+}
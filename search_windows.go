@@ -0,0 +1,88 @@
+//go:build windows
+
+package wh
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// platformSearchDirs returns the platform-specific directories appended to the
+// list returned by DefaultSearchDirs, which are the directories in the system
+// and user Path variables stored in the registry.
+func platformSearchDirs() []string {
+	var dirs []string
+	for _, k := range []struct {
+		root syscall.Handle
+		path string
+	}{
+		{syscall.HKEY_LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Control\Session Manager\Environment`},
+		{syscall.HKEY_CURRENT_USER, `Environment`},
+	} {
+		if v, ok := regString(k.root, k.path, "Path"); ok {
+			dirs = append(dirs, filepath.SplitList(expandEnv(v))...)
+		}
+	}
+	return dirs
+}
+
+// regString returns the string value with the given name stored in the given
+// registry key, and whether or not such a value was found.
+func regString(root syscall.Handle, key, name string) (string, bool) {
+	kp, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return "", false
+	}
+	np, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return "", false
+	}
+	var h syscall.Handle
+	if syscall.RegOpenKeyEx(root, kp, 0, syscall.KEY_READ, &h) != nil {
+		return "", false
+	}
+	defer syscall.RegCloseKey(h)
+	var typ, n uint32
+	if syscall.RegQueryValueEx(h, np, nil, &typ, nil, &n) != nil || n == 0 {
+		return "", false
+	}
+	if typ != syscall.REG_SZ && typ != syscall.REG_EXPAND_SZ {
+		return "", false
+	}
+	buf := make([]uint16, n/2+1)
+	if syscall.RegQueryValueEx(h, np, nil, &typ,
+		(*byte)(unsafe.Pointer(&buf[0])), &n) != nil {
+		return "", false
+	}
+	return syscall.UTF16ToString(buf), true
+}
+
+// expandEnv replaces each %VAR% in the given string s with the value of the
+// corresponding environment variable VAR. References to undefined variables
+// are left unmodified.
+func expandEnv(s string) string {
+	var sb strings.Builder
+	for {
+		i := strings.IndexByte(s, '%')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(s[i+1:], '%')
+		if j < 0 {
+			break
+		}
+		name := s[i+1 : i+1+j]
+		if v, ok := os.LookupEnv(name); ok && name != "" {
+			sb.WriteString(s[:i])
+			sb.WriteString(v)
+		} else {
+			sb.WriteString(s[:i+2+j])
+		}
+		s = s[i+2+j:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
@@ -0,0 +1,115 @@
+package wh
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidSortKey represents an error in which an unrecognized name of the
+// attribute by which to sort files was given.
+type ErrInvalidSortKey string
+
+// Error returns a descriptive error string for the receiver ErrInvalidSortKey e.
+func (e ErrInvalidSortKey) Error() string {
+	return "invalid sort key: " + strconv.Quote(string(e)) +
+		" (expected name, path, size, or mtime)"
+}
+
+// sortKey contains the attributes of a file by which it is sorted, along with
+// its index in the unsorted list of files.
+type sortKey struct {
+	index int
+	path  string
+	size  int64
+	mtime time.Time
+}
+
+// lessFunc reports whether the file with key a sorts before that with key b.
+type lessFunc func(a, b sortKey) bool
+
+// sortLess returns the lessFunc sorting files by the attribute with the given
+// name by, and whether or not the attribute must be read from the file system.
+// The returned lessFunc is nil if by is empty, which preserves the order files
+// were found in.
+func sortLess(by string) (less lessFunc, stat bool, err error) {
+	switch by {
+	case "":
+		return nil, false, nil
+	case "name":
+		return func(a, b sortKey) bool { return path.Base(a.path) < path.Base(b.path) }, false, nil
+	case "path":
+		return func(a, b sortKey) bool { return a.path < b.path }, false, nil
+	case "size":
+		return func(a, b sortKey) bool { return a.size < b.size }, true, nil
+	case "mtime":
+		return func(a, b sortKey) bool { return a.mtime.Before(b.mtime) }, true, nil
+	}
+	return nil, false, ErrInvalidSortKey(by)
+}
+
+// makeSortKey returns the sortKey of the file at index i with the given path p.
+// If stat is true, its attributes are read from the fs.FileInfo returned by the
+// given function info, or else it has size 0 and the zero modification time.
+func makeSortKey(i int, p string, stat bool, info func() (fs.FileInfo, error)) sortKey {
+	k := sortKey{index: i, path: p}
+	if stat {
+		if fi, err := info(); err == nil {
+			k.size, k.mtime = fi.Size(), fi.ModTime()
+		}
+	}
+	return k
+}
+
+// SortResults sorts the given file paths in place by the attribute with the
+// given name by: "name" (base name), "path", "size", or "mtime" (modification
+// time), each in ascending order. Files with equal attributes retain their
+// relative order. The order is not modified if by is empty.
+//
+// For "size" and "mtime", the attributes are those of the file to which each
+// path refers, after resolving symlinks. A file that cannot be read is sorted
+// as having size 0 and the zero modification time.
+func SortResults(results []string, by string) error {
+	less, stat, err := sortLess(by)
+	if err != nil || less == nil {
+		return err
+	}
+	key := make([]sortKey, len(results))
+	for i, p := range results {
+		key[i] = makeSortKey(i, p, stat, func() (fs.FileInfo, error) { return os.Stat(p) })
+	}
+	sorted := make([]string, len(results))
+	for i, k := range sortKeys(key, less) {
+		sorted[i] = results[k.index]
+	}
+	copy(results, sorted)
+	return nil
+}
+
+// sortResults sorts the given Results in place by the attribute with the given
+// name by, as with SortResults, comparing the Path of each Result.
+func sortResults(results []Result, by string) error {
+	less, stat, err := sortLess(by)
+	if err != nil || less == nil {
+		return err
+	}
+	key := make([]sortKey, len(results))
+	for i, r := range results {
+		key[i] = makeSortKey(i, r.Path, stat, r.Info)
+	}
+	sorted := make([]Result, len(results))
+	for i, k := range sortKeys(key, less) {
+		sorted[i] = results[k.index]
+	}
+	copy(results, sorted)
+	return nil
+}
+
+// sortKeys returns the given sortKeys key stably sorted by the given lessFunc.
+func sortKeys(key []sortKey, less lessFunc) []sortKey {
+	sort.SliceStable(key, func(i, j int) bool { return less(key[i], key[j]) })
+	return key
+}
@@ -0,0 +1,56 @@
+package wh
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestSortBy(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"b.txt":     "12",
+		"a/c.txt":   "123",
+		"d/a.txt":   "1",
+		"bb/bb.txt": "1234",
+	})
+	now := time.Now()
+	for i, name := range []string{"bb/bb.txt", "d/a.txt", "a/c.txt", "b.txt"} {
+		mtime := now.Add(time.Duration(i-10) * time.Hour)
+		if err := os.Chtimes(filepath.Join(dir, filepath.FromSlash(name)), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 2
+	for _, tt := range []struct {
+		by   string
+		want []string
+	}{
+		{"name", []string{"d/a.txt", "b.txt", "bb/bb.txt", "a/c.txt"}},
+		{"path", []string{"a/c.txt", "b.txt", "bb/bb.txt", "d/a.txt"}},
+		{"size", []string{"d/a.txt", "b.txt", "a/c.txt", "bb/bb.txt"}},
+		{"mtime", []string{"bb/bb.txt", "d/a.txt", "a/c.txt", "b.txt"}},
+	} {
+		option.SortBy = tt.by
+		found, err := Match(option, "*.txt", dir)
+		expect(t, found, err, join(dir, tt.want...)...)
+
+		option.SortBy = ""
+		found, err = Match(option, "*.txt", dir)
+		if err == nil {
+			err = SortResults(found, tt.by)
+		}
+		expect(t, found, err, join(dir, tt.want...)...)
+	}
+
+	option.SortBy = "color"
+	var key ErrInvalidSortKey
+	if _, err := Match(option, "*.txt", dir); !errors.As(err, &key) {
+		t.Fatalf("Match() error = %v, want ErrInvalidSortKey", err)
+	}
+}
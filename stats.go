@@ -0,0 +1,56 @@
+package wh
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// MatchStats contains statistics describing one or more searches.
+//
+// The counters of a MatchStats referred to by Option.Stats are updated
+// atomically, so that it may be shared by concurrent searches.
+type MatchStats struct {
+	DirsScanned int64         // Number of directories searched
+	Matches     int64         // Number of matching files reported
+	Elapsed     time.Duration // Total duration of the searches
+}
+
+// addDir increments the number of directories searched, if s is non-nil.
+func (s *MatchStats) addDir() {
+	if s != nil {
+		atomic.AddInt64(&s.DirsScanned, 1)
+	}
+}
+
+// addMatch increments the number of matching files reported, if s is non-nil.
+func (s *MatchStats) addMatch() {
+	if s != nil {
+		atomic.AddInt64(&s.Matches, 1)
+	}
+}
+
+// MatchReport returns the Result of each file found in each of the given
+// directories sub whose name matches the given string pattern according to
+// option.Expr, along with statistics describing the search.
+//
+// If option.Stats is non-nil, it is also updated with the statistics.
+func MatchReport(option Option, pattern string, sub ...string) ([]Result, MatchStats, error) {
+	var stats MatchStats
+	shared := option.Stats
+	option.Stats = &stats
+	start := time.Now()
+	var found []Result
+	err := match(context.Background(), option, option.fold(pattern), sub,
+		func(r Result) error {
+			found = append(found, r)
+			return nil
+		})
+	stats.Elapsed = time.Since(start)
+	if shared != nil {
+		atomic.AddInt64(&shared.DirsScanned, stats.DirsScanned)
+		atomic.AddInt64(&shared.Matches, stats.Matches)
+		atomic.AddInt64((*int64)(&shared.Elapsed), int64(stats.Elapsed))
+	}
+	return found, stats, err
+}
@@ -0,0 +1,23 @@
+package wh
+
+import "testing"
+
+func TestMatchReport(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "", "sub/a.go": "", "sub/b.go": ""})
+	option := DefaultOption()
+	option.MaxDepth = 2
+	var shared MatchStats
+	option.Stats = &shared
+	for i := 1; i <= 2; i++ {
+		found, stats, err := MatchReport(option, "a.go", dir)
+		if err != nil || len(found) != 2 {
+			t.Fatalf("MatchReport() = %v, %v", found, err)
+		}
+		if stats.DirsScanned != 2 || stats.Matches != 2 || stats.Elapsed <= 0 {
+			t.Fatalf("MatchReport() stats = %+v", stats)
+		}
+		if shared.DirsScanned != int64(2*i) || shared.Matches != int64(2*i) {
+			t.Fatalf("shared stats = %+v after %d searches", shared, i)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+//go:build unix
+
+package wh
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// symlink creates each of the given symlinks, keyed by path relative to the
+// given directory dir, to its target.
+func symlink(t *testing.T, dir string, link map[string]string) {
+	t.Helper()
+	for name, target := range link {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSymlinkCycle(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a/x": "", "b/x": "", "c/x": ""})
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Directories "a" and "b" link to each other, and "c" links to its parent.
+	symlink(t, dir, map[string]string{"a/toB": "../b", "b/toA": "../a", "c/up": ".."})
+
+	option := DefaultOption()
+	option.FollowSymlinks = true
+	option.MaxFollow = -1
+	option.MaxDepth = 8
+	var cycle []error
+	option.WalkErrHandler = func(path string, err error) error {
+		cycle = append(cycle, err)
+		return nil
+	}
+	var skip []string
+	option.TraceHook = func(e TraceEvent) {
+		if e.Skipped && e.Reason == "symlink cycle" {
+			skip = append(skip, e.Path)
+		}
+	}
+	// Each symlink is followed only until it would revisit a directory being
+	// searched, so "a/x" and "b/x" are each found once directly and once through
+	// a link, in the order visited ("a/toB" precedes "a/x").
+	found, err := Match(option, "x", dir)
+	expect(t, found, err, join(dir, "b/x", "a/x", "a/x", "b/x", "c/x")...)
+	want := join(dir, "b/toA", "a/toB", "c/up")
+	if !reflect.DeepEqual(skip, want) {
+		t.Fatalf("skipped %q, want %q", skip, want)
+	}
+	for i, w := range want {
+		if i >= len(cycle) || cycle[i] != ErrSymlinkCycle(w) {
+			t.Fatalf("WalkErrHandler errors %q, want ErrSymlinkCycle of each of %q", cycle, want)
+		}
+	}
+
+	// An error returned by WalkErrHandler stops searching the current search
+	// directory. For the cycles found through "a/toB" and "b/toA", that is the
+	// symlink target, so only "a/x" and "b/x" found directly remain. For "c/up",
+	// it is dir itself, so "c/x" is never reached.
+	stop := errors.New("stop")
+	option.WalkErrHandler = func(path string, err error) error { return stop }
+	found, err = Match(option, "x", dir)
+	if !errors.Is(err, stop) {
+		t.Fatalf("Match() error = %v, want %v", err, stop)
+	}
+	expect(t, found, nil, join(dir, "a/x", "b/x")...)
+}
+
+func TestMaxSymlinkChainLength(t *testing.T) {
+	dir := makeTree(t, map[string]string{"file": "", "loop/": ""})
+	// Symlink "a" reaches "file" through 2 other symlinks, and "loop/self" is a
+	// symlink to itself.
+	symlink(t, dir, map[string]string{"a": "b", "b": "c", "c": "file", "loop/self": "self"})
+
+	option := DefaultOption()
+	option.FollowSymlinks = true
+	option.MaxSymlinkChainLength = 4
+	res, err := MatchResults(option, "a", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := join(dir, "a", "b", "c", "file")
+	if len(res) != 1 || len(res[0].Chain) != len(want) {
+		t.Fatalf("MatchResults(a) = %v, want chain %q", res, want)
+	}
+	for i, l := range res[0].Chain {
+		if l.Path() != want[i] {
+			t.Fatalf("chain[%d] = %q, want %q", i, l.Path(), want[i])
+		}
+	}
+
+	// A chain longer than the limit, including any loop, stops the search with
+	// ErrSymlinkLoop naming the first symlink in the chain, so "file", which is
+	// visited after "a", is never found.
+	for _, tc := range []struct {
+		limit int
+		root  string
+		link  string
+	}{
+		{3, ".", "a"},
+		{1, ".", "a"},
+		{4, "loop", "loop/self"},
+	} {
+		option.MaxSymlinkChainLength = tc.limit
+		var loop ErrSymlinkLoop
+		found, err := Match(option, "file", join(dir, tc.root)[0])
+		if !errors.As(err, &loop) || loop != ErrSymlinkLoop(join(dir, tc.link)[0]) || len(found) != 0 {
+			t.Errorf("MaxSymlinkChainLength %d: Match(%q) = %q, %v, want ErrSymlinkLoop(%q)",
+				tc.limit, tc.root, found, err, tc.link)
+		}
+	}
+}
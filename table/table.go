@@ -0,0 +1,95 @@
+// Package table formats the results of a search as an aligned text table.
+package table
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ardnew/wh"
+)
+
+// ErrInvalidField represents an error in which an unrecognized field name was
+// given to Table.
+type ErrInvalidField string
+
+// Error returns a descriptive error string for the receiver ErrInvalidField e.
+func (e ErrInvalidField) Error() string {
+	return "invalid field: " + strconv.Quote(string(e))
+}
+
+// Fields contains the name of each field recognized by Table, in the order
+// used when no fields are given.
+var Fields = []string{"path", "size", "mtime", "perm", "depth"}
+
+// fieldFunc returns the string representation of a single attribute of the
+// file described by r.
+type fieldFunc func(r wh.Result) string
+
+// fieldFuncs maps each field name recognized by Table to the function
+// returning its string representation.
+var fieldFuncs = map[string]fieldFunc{
+	"path": func(r wh.Result) string { return r.Path },
+	"size": infoField(func(info fs.FileInfo) string {
+		return strconv.FormatInt(info.Size(), 10)
+	}),
+	"mtime": infoField(func(info fs.FileInfo) string {
+		return info.ModTime().Format(time.RFC3339)
+	}),
+	"perm": infoField(func(info fs.FileInfo) string {
+		return info.Mode().Perm().String()
+	}),
+	"depth": func(r wh.Result) string { return strconv.Itoa(r.Depth) },
+}
+
+// infoField returns a fieldFunc that calls the given function with the
+// fs.FileInfo of a file, or returns "-" if the file cannot be stat'd.
+func infoField(fn func(info fs.FileInfo) string) fieldFunc {
+	return func(r wh.Result) string {
+		info, err := r.Info()
+		if err != nil {
+			return "-"
+		}
+		return fn(info)
+	}
+}
+
+// Table writes the given fields of each of the given results to w as a table
+// of columns aligned with text/tabwriter, preceded by a header row containing
+// the upper-case name of each field.
+//
+// The recognized field names are listed in Fields, which are all used if no
+// fields are given. An error is returned for the first unrecognized field name
+// before anything is written, or for any error writing to w.
+func Table(results []wh.Result, fields []string, w io.Writer) error {
+	if len(fields) == 0 {
+		fields = Fields
+	}
+	fn := make([]fieldFunc, len(fields))
+	head := make([]string, len(fields))
+	for i, name := range fields {
+		f, ok := fieldFuncs[name]
+		if !ok {
+			return ErrInvalidField(name)
+		}
+		fn[i], head[i] = f, strings.ToUpper(name)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, strings.Join(head, "\t")); err != nil {
+		return err
+	}
+	row := make([]string, len(fn))
+	for _, r := range results {
+		for i, f := range fn {
+			row[i] = f(r)
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,75 @@
+package table
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ardnew/wh"
+	"github.com/ardnew/wh/expr"
+)
+
+func TestTable(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"a.go": "package a\n", "long_name.go": ""} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	option := wh.DefaultOption()
+	option.Expr = expr.Suffix
+	res, err := wh.MatchResults(option, ".go", dir)
+	if err != nil || len(res) != 2 {
+		t.Fatalf("MatchResults() = %v, %v", res, err)
+	}
+
+	var buf bytes.Buffer
+	if err := Table(res, []string{"size", "depth", "perm"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(res[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	perm := info.Mode().Perm().String() // Not 0o644 on all platforms
+	want := "SIZE  DEPTH  PERM\n" +
+		"10    1      " + perm + "\n" +
+		"0     1      " + perm + "\n"
+	if buf.String() != want {
+		t.Fatalf("Table() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	if err := os.Remove(res[1].Path); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := Table(res, nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	line := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(line) != 3 || strings.Join(strings.Fields(line[0]), " ") != "PATH SIZE MTIME PERM DEPTH" ||
+		strings.Join(strings.Fields(line[2]), " ") != res[1].Path+" - - - 1" {
+		t.Fatalf("Table() =\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := Table(res, []string{"path", "color"}, &buf); err != ErrInvalidField("color") || buf.Len() != 0 {
+		t.Fatalf("Table(invalid field) = %v, wrote %q", err, buf.String())
+	}
+	if ErrInvalidField("color").Error() == "" {
+		t.Fatal("ErrInvalidField.Error() = \"\"")
+	}
+	fail := errors.New("fail")
+	if err := Table(res, nil, failWriter{fail}); err != fail {
+		t.Fatalf("Table(failing writer) error = %v, want %v", err, fail)
+	}
+}
+
+// failWriter is an io.Writer whose Write method always returns err.
+type failWriter struct{ err error }
+
+// Write returns the receiver failWriter's error.
+func (w failWriter) Write([]byte) (int, error) { return 0, w.err }
@@ -0,0 +1,108 @@
+package wh
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// MatchTemplate returns each file found in the given directories sub for which
+// the given text/template tmpl evaluates to "true" when executed with the file's
+// Result as its data. Leading and trailing white space in the template output
+// is ignored, and any other output rejects the file.
+//
+// For example, the following template reports only files larger than 1 KiB
+// that were found in a subdirectory:
+//
+//	{{ and (gt .Depth 1) (gt .Info.Size 1024) }}
+//
+// The template is parsed before searching, so that a parse error is returned
+// immediately. An error executing the template stops the search.
+func MatchTemplate(option Option, tmpl string, sub ...string) ([]string, error) {
+	t, err := template.New("MatchTemplate").Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	option.matcher = func(string, string) (bool, error) { return true, nil }
+	option.predicate = func(r Result) (bool, error) {
+		var sb strings.Builder
+		if err := t.Execute(&sb, r); err != nil {
+			return false, err
+		}
+		return strings.TrimSpace(sb.String()) == "true", nil
+	}
+	var found []string
+	err = match(context.Background(), option, "", sub, func(r Result) error {
+		found = append(found, r.String())
+		return nil
+	})
+	return found, err
+}
+
+// ResultInfo describes a file for formatting by FormatResult.
+type ResultInfo struct {
+	Path         string      // Path of the file, as given to FormatResult
+	Name         string      // Base name of the file
+	Dir          string      // Directory containing the file
+	Size         int64       // Size in bytes of the file, after resolving symlinks
+	ModTime      time.Time   // Modification time, after resolving symlinks
+	Mode         fs.FileMode // Mode bits, after resolving symlinks
+	IsSymlink    bool        // Whether or not the file is a symlink
+	IsExecutable bool        // Whether or not the file is an executable file
+}
+
+// FormatResult writes the output of executing the given text/template tmpl to
+// the given io.Writer w, with the ResultInfo of the file at the given path as
+// its data. For example, the template "{{.Path}}" writes only the path.
+//
+// If the file is a symlink that cannot be resolved, Size, ModTime, and Mode
+// describe the symlink itself.
+func FormatResult(w io.Writer, tmpl string, path string) error {
+	t, err := template.New("FormatResult").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	linfo, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		info = linfo
+	}
+	return formatResult(w, t, path, linfo, info)
+}
+
+// FormatFileInfo writes the output of executing the given text/template tmpl to
+// the given io.Writer w, as with FormatResult, except that the attributes of
+// the file at the given path are those of the given fs.FileInfo info rather
+// than read from the file system. This formats files that do not exist on the
+// host file system, such as those found with MatchZipResults.
+func FormatFileInfo(w io.Writer, tmpl string, path string, info fs.FileInfo) error {
+	t, err := template.New("FormatResult").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	return formatResult(w, t, path, info, info)
+}
+
+// formatResult executes the given template t with the ResultInfo of the file
+// at the given path, whose own attributes are linfo, and whose attributes after
+// resolving symlinks are info.
+func formatResult(w io.Writer, t *template.Template, path string, linfo, info fs.FileInfo) error {
+	return t.Execute(w, ResultInfo{
+		Path:         path,
+		Name:         filepath.Base(path),
+		Dir:          filepath.Dir(path),
+		Size:         info.Size(),
+		ModTime:      info.ModTime(),
+		Mode:         info.Mode(),
+		IsSymlink:    linfo.Mode()&fs.ModeSymlink != 0,
+		IsExecutable: !info.IsDir() && isExecutable(info),
+	})
+}
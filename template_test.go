@@ -0,0 +1,36 @@
+package wh
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatResult(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.txt": "hello"})
+	var sb strings.Builder
+	err := FormatResult(&sb, "{{.Name}} {{.Size}} {{.IsSymlink}}", filepath.Join(dir, "a.txt"))
+	if err != nil || sb.String() != "a.txt 5 false" {
+		t.Fatalf("FormatResult() = %q, %v", sb.String(), err)
+	}
+}
+
+func TestMalformedTemplate(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.txt": "hello"})
+	var sb strings.Builder
+	err := FormatResult(&sb, "{{.Path", filepath.Join(dir, "a.txt"))
+	if err == nil || !strings.Contains(err.Error(), "template") || sb.Len() != 0 {
+		t.Fatalf("FormatResult() = %q, %v, want template error", sb.String(), err)
+	}
+
+	// The template is parsed before any file is examined.
+	option := DefaultOption()
+	events := 0
+	option.TraceHook = func(TraceEvent) { events++ }
+	found, err := MatchTemplate(option, "{{ gt .Depth", dir)
+	if err == nil || !strings.Contains(err.Error(), "template") || found != nil || events != 0 {
+		t.Fatalf("MatchTemplate() = %q, %v after %d events, want template error", found, err, events)
+	}
+	found, err = MatchTemplate(option, "{{ eq .Name \"a.txt\" }}", dir)
+	expect(t, found, err, join(dir, "a.txt")...)
+}
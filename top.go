@@ -0,0 +1,79 @@
+package wh
+
+import (
+	"container/heap"
+	"context"
+	"io/fs"
+)
+
+// MatchRecent returns the n most recently modified files matching the given
+// string pattern according to option.Expr, ordered from newest to oldest.
+func MatchRecent(n int, option Option, pattern string, sub ...string) ([]string, error) {
+	return matchTop(n, option, pattern, sub,
+		func(info fs.FileInfo) int64 { return info.ModTime().UnixNano() })
+}
+
+// MatchLargest returns the n largest files matching the given string pattern
+// according to option.Expr, ordered from largest to smallest.
+func MatchLargest(n int, option Option, pattern string, sub ...string) ([]string, error) {
+	return matchTop(n, option, pattern, sub,
+		func(info fs.FileInfo) int64 { return info.Size() })
+}
+
+// MatchSmallest returns the n smallest files matching the given string pattern
+// according to option.Expr, ordered from smallest to largest.
+func MatchSmallest(n int, option Option, pattern string, sub ...string) ([]string, error) {
+	return matchTop(n, option, pattern, sub,
+		func(info fs.FileInfo) int64 { return -info.Size() })
+}
+
+// rankedResult associates a Result with the key used to rank it in a rankHeap.
+type rankedResult struct {
+	result Result
+	key    int64
+}
+
+// rankHeap implements heap.Interface as a min-heap of rankedResult ordered by
+// key, such that the root element has the least key.
+type rankHeap []rankedResult
+
+func (h rankHeap) Len() int            { return len(h) }
+func (h rankHeap) Less(i, j int) bool  { return h[i].key < h[j].key }
+func (h rankHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *rankHeap) Push(x interface{}) { *h = append(*h, x.(rankedResult)) }
+func (h *rankHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// matchTop returns the n matching files with the greatest key, as computed by
+// the given function from each file's fs.FileInfo, ordered from greatest to
+// least. Files that cannot be stat'd are ignored.
+func matchTop(n int, option Option, pattern string, sub []string,
+	key func(fs.FileInfo) int64) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	h := make(rankHeap, 0, n)
+	err := match(context.Background(), option, option.fold(pattern), sub, func(r Result) error {
+		info, err := r.Chain.Head().Stat()
+		if err != nil {
+			return nil // Just ignore the file if there is any error.
+		}
+		rc := rankedResult{result: r, key: key(info)}
+		if h.Len() < n {
+			heap.Push(&h, rc)
+		} else if rc.key > h[0].key {
+			h[0] = rc
+			heap.Fix(&h, 0)
+		}
+		return nil
+	})
+	found := make([]string, h.Len())
+	for i := len(found) - 1; i >= 0; i-- {
+		found[i] = heap.Pop(&h).(rankedResult).result.String()
+	}
+	return found, err
+}
@@ -0,0 +1,46 @@
+package wh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestMatchTop(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a": "1", "b": "123", "c": "12", "d": "1234"})
+	now := time.Now()
+	for i, name := range []string{"b", "d", "a", "c"} {
+		mtime := now.Add(time.Duration(i) * time.Hour)
+		if err := os.Chtimes(filepath.Join(dir, name), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	found, err := MatchRecent(2, option, "*", dir)
+	expect(t, found, err, join(dir, "c", "a")...)
+	found, err = MatchLargest(3, option, "*", dir)
+	expect(t, found, err, join(dir, "d", "b", "c")...)
+	found, err = MatchSmallest(10, option, "*", dir)
+	expect(t, found, err, join(dir, "a", "c", "b", "d")...)
+	found, err = MatchLargest(0, option, "*", dir)
+	expect(t, found, err)
+}
+
+func TestMatchTopRelative(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a": "1", "sub/b": "12", "sub/c": "123"})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 2
+	option.RelativeTo = join(dir, "sub")[0]
+	want, err := Match(option, "[bc]", dir)
+	expect(t, want, err, "b", "c")
+	found, err := MatchLargest(2, option, "*", dir)
+	expect(t, found, err, "c", "b")
+	option.RelativeTo = dir
+	found, err = MatchSmallest(3, option, "*", dir)
+	expect(t, found, err, join("", "a", "sub/b", "sub/c")...)
+}
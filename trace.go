@@ -0,0 +1,61 @@
+package wh
+
+// TraceKind enumerates the kinds of events reported to Option.TraceHook.
+type TraceKind int
+
+// Enumerated constants of type TraceKind.
+const (
+	TraceFile    TraceKind = iota // File examined but not matched
+	TraceDir                      // Directory searched
+	TraceSymlink                  // Symlink dereferenced
+	TraceSkip                     // File or directory skipped
+	TraceMatch                    // File matched and reported
+)
+
+// String returns a string representation of the receiver TraceKind k.
+func (k TraceKind) String() string {
+	switch k {
+	case TraceFile:
+		return "file"
+	case TraceDir:
+		return "dir"
+	case TraceSymlink:
+		return "symlink"
+	case TraceSkip:
+		return "skip"
+	case TraceMatch:
+		return "match"
+	}
+	return "unknown"
+}
+
+// TraceEvent describes a single file or directory encountered while searching.
+type TraceEvent struct {
+	Kind    TraceKind // Kind of event
+	Path    string    // Path of the file or directory
+	Depth   int       // Number of path components between search directory and Path
+	Matched bool      // File was reported (Kind == TraceMatch)
+	Skipped bool      // File or directory was skipped (Kind == TraceSkip)
+	Reason  string    // Reason the file or directory was skipped, if Skipped
+}
+
+// TraceFunc is the signature of the function called for each TraceEvent.
+// It may be called concurrently if Option.Parallel is greater than 1.
+type TraceFunc func(event TraceEvent)
+
+// trace calls option.TraceHook, if non-nil, with a TraceEvent of the given
+// kind for the file at path p found at the given depth, and records the event
+// in option.AuditLog, if non-nil.
+func (option Option) trace(kind TraceKind, p string, depth int, reason string) {
+	option.audit(kind, p, reason)
+	if option.TraceHook != nil {
+		option.TraceHook(TraceEvent{
+			Kind:    kind,
+			Path:    p,
+			Depth:   depth,
+			Matched: kind == TraceMatch,
+			Skipped: kind == TraceSkip,
+			Reason:  reason,
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package wh
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTraceHook(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "", "b.txt": "", "sub/c.go": ""})
+	option := DefaultOption()
+	var audit bytes.Buffer
+	var event []TraceEvent
+	option.TraceHook = func(e TraceEvent) { event = append(event, e) }
+	option.AuditLog = &audit
+	found, err := Match(option, "a.go", dir)
+	expect(t, found, err, join(dir, "a.go")...)
+
+	var kind []string
+	for _, e := range event {
+		if e.Matched != (e.Kind == TraceMatch) || e.Skipped != (e.Kind == TraceSkip) {
+			t.Errorf("inconsistent TraceEvent %+v", e)
+		}
+		kind = append(kind, e.Kind.String())
+	}
+	want := []string{"dir", "match", "file", "skip"}
+	if !reflect.DeepEqual(kind, want) {
+		t.Fatalf("TraceEvent kinds = %q, want %q", kind, want)
+	}
+	if event[3].Reason == "" || filepath.Base(event[3].Path) != "sub" {
+		t.Fatalf("skip TraceEvent = %+v", event[3])
+	}
+	if TraceKind(-1).String() != "unknown" || TraceSymlink.String() != "symlink" {
+		t.Fatal("TraceKind.String() is incorrect")
+	}
+
+	var action []string
+	dec := json.NewDecoder(&audit)
+	for dec.More() {
+		var rec auditRecord
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		action = append(action, rec.Action)
+	}
+	if want := []string{"visited", "matched", "visited", "skipped"}; !reflect.DeepEqual(action, want) {
+		t.Fatalf("audit actions = %q, want %q", action, want)
+	}
+}
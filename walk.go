@@ -0,0 +1,161 @@
+package wh
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// walkDir walks the file tree rooted at root, calling fn for each file or
+// directory in the tree, including root.
+//
+// It has the same semantics as fs.WalkDir, except that the entries of each
+// directory are visited in lexical order only if option.Sort is true, even if
+// the file system fsys does not guarantee any particular order. Otherwise, the
+// entries are visited in the order returned by the file system.
+//
+// If option.BreadthFirst is true, all entries at each depth of the tree are
+// visited before any entry at the following depth.
+//
+// If option.ParallelReadDir is true, the entries of each subdirectory are read
+// concurrently in advance of visiting the subdirectory, but fn is still called
+// sequentially and in the same order.
+func (option Option) walkDir(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	if option.ParallelReadDir && option.pre == nil {
+		option.pre = newPrefetch()
+		defer option.pre.wait()
+	}
+	info, err := fs.Stat(fsys, root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else if option.BreadthFirst {
+		err = option.walkBFS(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	} else {
+		err = option.walk(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walk recursively descends the given directory name, calling fn for each of
+// its entries.
+func (option Option) walk(fsys fs.FS, name string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(name, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			// Successfully skipped directory.
+			err = nil
+		}
+		return err
+	}
+
+	ents, err := option.readDir(fsys, name)
+	option.prefetch(fsys, name, ents)
+	if err != nil {
+		// Second call, to report ReadDir error.
+		err = fn(name, d, err)
+		if err != nil {
+			if err == fs.SkipDir && d.IsDir() {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, e := range ents {
+		if err := option.walk(fsys, path.Join(name, e.Name()), e, fn); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// walkBFS visits the given directory name and its descendents in breadth-first
+// order, calling fn for each, with the same semantics as walk.
+func (option Option) walkBFS(fsys fs.FS, name string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(name, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			// Successfully skipped directory.
+			err = nil
+		}
+		return err
+	}
+
+	type dir struct {
+		name string
+		d    fs.DirEntry
+	}
+	// Each directory in queue has been visited, but its entries have not.
+	queue := []dir{{name: name, d: d}}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+
+		ents, err := option.readDir(fsys, curr.name)
+		option.prefetch(fsys, curr.name, ents)
+		if err != nil {
+			// Second call, to report ReadDir error.
+			if err = fn(curr.name, curr.d, err); err != nil {
+				if err == fs.SkipDir {
+					continue
+				}
+				return err
+			}
+		}
+
+		for _, e := range ents {
+			name := path.Join(curr.name, e.Name())
+			if err := fn(name, e, nil); err != nil {
+				if err == fs.SkipDir {
+					if e.IsDir() {
+						continue // Skip only this directory.
+					}
+					break // Skip the remaining entries in the parent directory.
+				}
+				return err
+			}
+			if e.IsDir() {
+				queue = append(queue, dir{name: name, d: e})
+			}
+		}
+	}
+	return nil
+}
+
+// readDir returns the entries of the named directory in fsys, sorted by file
+// name if option.Sort is true.
+//
+// If the entries were read in advance by option.prefetch, those are returned.
+func (option Option) readDir(fsys fs.FS, name string) ([]fs.DirEntry, error) {
+	if option.pre != nil {
+		if d, ok := option.pre.get(name); ok {
+			return d.ents, d.err
+		}
+	}
+	return option.read(fsys, name)
+}
+
+// read returns the entries of the named directory in fsys, sorted by file name
+// if option.Sort is true.
+func (option Option) read(fsys fs.FS, name string) ([]fs.DirEntry, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir, ok := f.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name,
+			Err: errors.New("not implemented")}
+	}
+	ents, err := dir.ReadDir(-1)
+	if option.Sort {
+		sort.Slice(ents, func(i, j int) bool { return ents[i].Name() < ents[j].Name() })
+	}
+	return ents, err
+}
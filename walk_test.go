@@ -0,0 +1,240 @@
+package wh
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// reverseFS is a file system whose directories list their entries in reverse
+// lexical order.
+type reverseFS struct{ fstest.MapFS }
+
+// reverseDir is a directory of a reverseFS.
+type reverseDir struct{ fs.ReadDirFile }
+
+// Open opens the named file of the receiver reverseFS r.
+func (r reverseFS) Open(name string) (fs.File, error) {
+	f, err := r.MapFS.Open(name)
+	if d, ok := f.(fs.ReadDirFile); ok {
+		return reverseDir{d}, err
+	}
+	return f, err
+}
+
+// ReadDir returns the entries of the receiver reverseDir d in reverse order.
+func (d reverseDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	ents, err := d.ReadDirFile.ReadDir(n)
+	for i, j := 0, len(ents)-1; i < j; i, j = i+1, j-1 {
+		ents[i], ents[j] = ents[j], ents[i]
+	}
+	return ents, err
+}
+
+func TestMatchDeterministic(t *testing.T) {
+	file := map[string]string{}
+	for _, name := range []string{"c.go", "a.go", "b/z.go", "b/y.go", "d.go", "e/x.go"} {
+		file[name] = ""
+	}
+	dir := makeTree(t, file)
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 2
+	first, err := Match(option, "*.go", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		found, err := Match(option, "*.go", dir)
+		expect(t, found, err, first...)
+	}
+	expect(t, first, nil, join(dir, "a.go", "b/y.go", "b/z.go", "c.go", "d.go", "e/x.go")...)
+}
+
+func TestSortReadDir(t *testing.T) {
+	fsys := reverseFS{fstest.MapFS{
+		"a.go": {}, "b.go": {}, "c.go": {},
+	}}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	found, err := MatchFS(fsys, option, "*.go", ".")
+	expect(t, found, err, "a.go", "b.go", "c.go")
+
+	option.Sort = false
+	found, err = MatchFS(fsys, option, "*.go", ".")
+	expect(t, found, err, "c.go", "b.go", "a.go")
+}
+
+func TestBreadthFirst(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"a/b/x.go": "", "a/x.go": "", "c/x.go": "", "x.go": "",
+	})
+	option := DefaultOption()
+	option.MaxDepth = 3
+	found, err := Match(option, "x.go", dir)
+	expect(t, found, err, join(dir, "a/b/x.go", "a/x.go", "c/x.go", "x.go")...)
+	option.BreadthFirst = true
+	found, err = Match(option, "x.go", dir)
+	expect(t, found, err, join(dir, "x.go", "a/x.go", "c/x.go", "a/b/x.go")...)
+	option.ParallelReadDir = true
+	found, err = Match(option, "x.go", dir)
+	expect(t, found, err, join(dir, "x.go", "a/x.go", "c/x.go", "a/b/x.go")...)
+}
+
+func TestParallelReadDir(t *testing.T) {
+	file := map[string]string{}
+	for _, d := range []string{"a", "b", "c", "d"} {
+		for _, e := range []string{"1", "2", "3"} {
+			file[d+"/"+e+"/x.go"] = ""
+			file[d+"/"+e+"/y.go"] = ""
+		}
+	}
+	dir := makeTree(t, file)
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	option.MaxDepth = 3
+	want, err := Match(option, "x.*", dir)
+	if err != nil || len(want) != 12 {
+		t.Fatalf("Match() = %q, %v", want, err)
+	}
+	option.ParallelReadDir = true
+	found, err := Match(option, "x.*", dir)
+	expect(t, found, err, want...)
+	option.MaxDepth = 2
+	found, err = Match(option, "x.*", dir)
+	expect(t, found, err)
+}
+
+func TestParallel(t *testing.T) {
+	file := map[string]string{}
+	var sub, want []string
+	for _, d := range []string{"a", "b", "c", "d", "e", "f"} {
+		sub = append(sub, d)
+		for _, e := range []string{"1", "2", "3"} {
+			file[d+"/"+e+".go"] = ""
+			want = append(want, d+"/"+e+".go")
+		}
+	}
+	dir := makeTree(t, file)
+	want = join(dir, want...)
+	sub = join(dir, sub...)
+	option := DefaultOption()
+	option.Expr = expr.Suffix
+	option.Parallel = 3
+	option.PreserveOrder = true
+	found, err := Match(option, ".go", sub...)
+	expect(t, found, err, want...)
+
+	option.PreserveOrder = false
+	found, err = Match(option, ".go", sub...)
+	if err != nil || len(found) != len(want) {
+		t.Fatalf("Match() = %q, %v", found, err)
+	}
+	option.SortBy = "path"
+	found, err = Match(option, ".go", sub...)
+	expect(t, found, err, want...)
+
+	option.SortBy = ""
+	option.MaxResults = 4
+	found, err = Match(option, ".go", sub...)
+	if err != nil || len(found) != 4 {
+		t.Fatalf("Match(MaxResults 4) = %q, %v", found, err)
+	}
+
+	option.MaxResults = 0
+	missing := append(append([]string{}, sub...), join(dir, "missing")...)
+	found, err = Match(option, ".go", missing...)
+	var werr ErrWalkDir
+	if len(found) != len(want) || !errors.As(err, &werr) || len(werr) != 1 {
+		t.Fatalf("Match(missing) = %q, %v", found, err)
+	}
+
+	fail := errors.New("stop")
+	err = match(context.Background(), option, ".go", sub, func(Result) error { return fail })
+	if !errors.Is(err, fail) {
+		t.Fatalf("match(failing visit) error = %v, want %v", err, fail)
+	}
+}
+
+func TestMaxResults(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "", "b.go": "", "c.go": ""})
+	option := DefaultOption()
+	option.Expr = expr.Suffix
+	option.MaxResults = 2
+	found, err := Match(option, ".go", dir)
+	expect(t, found, err, join(dir, "a.go", "b.go")...)
+	option.MaxResults = 5
+	found, err = Match(option, ".go", dir)
+	expect(t, found, err, join(dir, "a.go", "b.go", "c.go")...)
+}
+
+func TestMaxDirs(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a/x": "", "b/x": "", "c/x": ""})
+	option := DefaultOption()
+	option.MaxDepth = 2
+	option.MaxDirs = 2
+	found, err := Match(option, "x", join(dir, "a", "b", "c")...)
+	if err != ErrMaxDirs(2) || len(found) != 2 {
+		t.Fatalf("Match(MaxDirs 2) = %q, %v", found, err)
+	}
+	option.MaxDirs = 3
+	found, err = Match(option, "x", join(dir, "a", "b", "c")...)
+	expect(t, found, err, join(dir, "a/x", "b/x", "c/x")...)
+}
+
+func TestMaxDepth(t *testing.T) {
+	dir := makeTree(t, map[string]string{"x": "", "a/x": "", "a/b/x": ""})
+	option := DefaultOption()
+	for depth, want := range [][]string{
+		nil,
+		join(dir, "x"),
+		join(dir, "a/x", "x"),
+		join(dir, "a/b/x", "a/x", "x"),
+	} {
+		option.MaxDepth = depth
+		found, err := Match(option, "x", dir)
+		expect(t, found, err, want...)
+	}
+}
+
+func BenchmarkTraversalOrder(b *testing.B) {
+	// A balanced tree with 4 subdirectories per directory, 4 levels deep, with a
+	// single match at depth 2 in the last subdirectory searched depth-first.
+	const fanout, levels = 4, 4
+	file := map[string]string{"d3/match": ""}
+	dirs := []string{""}
+	for l := 0; l < levels; l++ {
+		var next []string
+		for _, d := range dirs {
+			for i := 0; i < fanout; i++ {
+				next = append(next, d+"d"+strconv.Itoa(i)+"/")
+			}
+		}
+		for _, d := range next {
+			file[d+"file"] = ""
+		}
+		dirs = next
+	}
+	dir := makeTree(b, file)
+	option := DefaultOption()
+	option.MaxDepth = levels + 1
+	option.MaxResults = 1
+	for _, order := range []struct {
+		name         string
+		breadthFirst bool
+	}{{"DepthFirst", false}, {"BreadthFirst", true}} {
+		option.BreadthFirst = order.breadthFirst
+		b.Run(order.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if found, err := Match(option, "match", dir); err != nil || len(found) != 1 {
+					b.Fatalf("Match() = %q, %v", found, err)
+				}
+			}
+		})
+	}
+}
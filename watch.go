@@ -0,0 +1,31 @@
+package wh
+
+import "time"
+
+// Watcher reports the files matching a pattern that have been modified since
+// the previous search.
+//
+// Watcher is not safe for concurrent use from multiple goroutines.
+type Watcher struct {
+	option Option
+}
+
+// WatchSince returns a new Watcher using the given search and match options.
+// The first call to Changed reports files modified after option.After,
+// which reports all matching files if it is the zero time.
+func WatchSince(option Option) *Watcher {
+	return &Watcher{option: option}
+}
+
+// Changed returns the files matching the given string pattern according to the
+// Watcher's option.Expr that have been modified since the previous call to
+// Changed.
+//
+// The time recorded for each call is the time at which the call began, so that
+// files modified during a search are reported again by the following call.
+func (w *Watcher) Changed(pattern string, sub ...string) ([]string, error) {
+	now := time.Now()
+	found, err := Match(w.option, w.option.fold(pattern), sub...)
+	w.option.After = now
+	return found, err
+}
@@ -0,0 +1,34 @@
+package wh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ardnew/wh/expr"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.txt": "", "b.txt": ""})
+	past := time.Now().Add(-time.Hour)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.Chtimes(filepath.Join(dir, name), past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	w := WatchSince(option)
+	found, err := w.Changed("*.txt", dir)
+	expect(t, found, err, join(dir, "a.txt", "b.txt")...)
+	found, err = w.Changed("*.txt", dir)
+	expect(t, found, err)
+
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "b.txt"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	found, err = w.Changed("*.txt", dir)
+	expect(t, found, err, join(dir, "b.txt")...)
+}
@@ -1,26 +1,112 @@
 package wh
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ardnew/wh/expr"
 )
 
 // Option defines all search and match options for the exported Match functions.
 type Option struct {
-	MaxFollow      int       // Maximum number symlink components to follow
-	MaxDepth       int       // Maximum number of subdirectory recursions
-	Expr           expr.Expr // Matching semantics of the given pattern
-	WorkingDir     string    // Current working directory
-	fromDepth      int       // Depth prior to dereferencing a symlink
-	fromFollow     int       // Number of Links resolved
-	FollowSymlinks bool      // Follow symlinks when recursing into subdirectories
-	IgnoreCase     bool      // Ignore case in matching semantics
+	MaxFollow             int         // Maximum number symlink components to follow
+	MaxDepth              int         // Maximum number of subdirectory recursions
+	MaxSymlinkChainLength int         // Maximum length of a single symlink chain (0 = unlimited)
+	MaxResults            int         // Maximum number of files to report (0 = unlimited)
+	MaxDirs               int         // Maximum number of directories to search (0 = unlimited)
+	FuzzyThreshold        int         // Maximum edit distance of names matching a Fuzzy pattern
+	Parallel              int         // Number of directories searched concurrently
+	OwnerUID              int         // Match only files owned by this user ID if FilterOwner (-1 = any)
+	OwnerGID              int         // Match only files owned by this group ID if FilterOwner (-1 = any)
+	MinSize               int64       // Match only files of at least this many bytes (0 = any)
+	MaxSize               int64       // Match only files of at most this many bytes (0 = any)
+	Expr                  expr.Expr   // Matching semantics of the given pattern
+	WorkingDir            string      // Current working directory
+	EnvVar                string      // Environment variable containing search paths
+	RelativeTo            string      // Report paths relative to this directory if set
+	RequiredParentDir     string      // Match only files whose parent has this name
+	ContentPattern        string      // Match only files with a line matching this regexp
+	ScriptInterpreter     string      // Match only scripts whose shebang line contains this
+	SortBy                string      // Sort files found by name, path, size, or mtime if set
+	After                 time.Time   // Match only files modified after this time
+	Before                time.Time   // Match only files modified before this time
+	RequireAccess         fs.FileMode // Match only files with these access rights (0 = any)
+	AllowedDirs           []string    // Descend only into subdirectories with these names
+	Exclude               []string    // Ignore files whose name matches any of these patterns
+	WalkErrHandler        WalkErrFunc `json:"-"` // Handles errors encountered reading directories
+	TraceHook             TraceFunc   `json:"-"` // Called for each file and directory if non-nil
+	Stats                 *MatchStats `json:"-"` // Accumulates search statistics if non-nil
+	AuditLog              io.Writer   `json:"-"` // Records each file accessed as NDJSON if non-nil
+	fromDepth             int         // Depth prior to dereferencing a symlink
+	fromFollow            int         // Number of Links resolved
+	dirs                  *int64      // Number of directories searched, if MaxDirs > 0
+	pre                   *prefetcher // Directories read in advance, if ParallelReadDir
+	fsys                  fs.FS       // File system searched (nil = host OS)
+	matcher               matchFunc   // Overrides Expr.Match if non-nil
+	predicate             resultFunc  // Reports whether a Result is visited if non-nil
+	staged                gitSet      // Files staged in git, if GitStagedOnly
+	tracked               gitSet      // Files tracked in git, if GitTrackedOnly
+	module                string      // Go module root prior to dereferencing a symlink
+	ancestors             []fileID    // Directories containing the search directory
+	FollowSymlinks        bool        // Follow symlinks when recursing into subdirectories
+	IgnoreCase            bool        // Ignore case in matching semantics
+	SkipHidden            bool        // Ignore hidden files and directories
+	HiddenOnly            bool        // Ignore files and directories not hidden
+	Sort                  bool        // Search directory entries in lexical order
+	BreadthFirst          bool        // Search all entries at each depth before descending
+	AbsolutePaths         bool        // Report absolute paths regardless of search directory
+	SkipMountPoints       bool        // Do not descend into directories on other devices
+	GitStagedOnly         bool        // Match only files staged in the git index
+	GitTrackedOnly        bool        // Match only files tracked or not ignored by git
+	PreserveOrder         bool        // Report files in search directory order if Parallel
+	ParallelReadDir       bool        // Read subdirectories concurrently while searching
+	SymlinksAsFiles       bool        // Match symlinks by name without dereferencing them
+	DanglingOnly          bool        // Match only symlinks whose target does not exist
+	HardlinksOnly         bool        // Match only files with more than one hard link
+	FilterOwner           bool        // Match only files owned by OwnerUID and OwnerGID (Unix only)
+	ContentHash           bool        // Compare file content by SHA-256 hash in MatchDuplicates
+	ExecutableOnly        bool        // Match only executable files
+	BinaryOnly            bool        // Match only ELF, Mach-O, or PE executables
+	GoModuleAware         bool        // Count MaxDepth from each directory with a go.mod
+	Unique                bool        // Report only the first file with each canonical path
+
+	StringAlgo StringSearchAlgo // Algorithm used to match Fixed and Suffix patterns
+	FileTypes  FileTypeMask     // Match only files of these types (0 = TypeRegular)
+}
+
+// WalkErrFunc is the signature of the function called to handle an error err
+// encountered while reading the directory at path. Its return value is used by
+// fs.WalkDir: nil continues the search, fs.SkipDir skips the directory, and
+// any other error stops searching the current search directory. It may be
+// called concurrently if Option.Parallel is greater than 1.
+type WalkErrFunc func(path string, err error) error
+
+// DefaultOption returns an Option initialized with the default value of each
+// field, which are also the defaults used by the wh command.
+func DefaultOption() Option {
+	return Option{
+		MaxDepth:       1,
+		FuzzyThreshold: expr.FuzzyThreshold,
+		OwnerUID:       -1,
+		OwnerGID:       -1,
+		Expr:           expr.Fixed,
+		EnvVar:         "PATH",
+		Sort:           true,
+		FileTypes:      TypeRegular,
+		ContentHash:    true,
+	}
 }
 
 // MatchFunc is the signature of each of the exported matching functions.
@@ -29,31 +115,271 @@ type MatchFunc func(Option, string, ...string) ([]string, error)
 // MatchFixed returns the result of calling Match with the given string pattern
 // used to match file names verbatim.
 func MatchFixed(option Option, pattern string, sub ...string) ([]string, error) {
+	return MatchFixedWithContext(context.Background(), option, pattern, sub...)
+}
+
+// MatchFixedWithContext returns the result of calling MatchWithContext with the
+// given string pattern used to match file names verbatim.
+func MatchFixedWithContext(ctx context.Context, option Option, pattern string, sub ...string) ([]string, error) {
 	option.Expr = expr.Fixed
 	if option.IgnoreCase {
 		pattern = strings.ToLower(pattern)
 	}
-	return Match(option, pattern, sub...)
+	return MatchWithContext(ctx, option, pattern, sub...)
+}
+
+// MatchFixedFile returns the result of calling MatchAny with the patterns read
+// from the named file patternsFile, each used to match file names verbatim.
+// The file contains one pattern per line. Empty lines and lines beginning with
+// '#' are ignored.
+func MatchFixedFile(option Option, patternsFile string, sub ...string) ([]string, error) {
+	pattern, err := readPatterns(patternsFile)
+	if err != nil {
+		return nil, err
+	}
+	option.Expr = expr.Fixed
+	return MatchAny(option, pattern, sub...)
+}
+
+// readPatterns returns each line of the named file that is neither empty nor
+// begins with '#'.
+func readPatterns(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadPatterns(f)
+}
+
+// LoadPatterns returns each line read from the given io.Reader r that is
+// neither empty nor begins with '#', or ErrNoPatterns if there are no such
+// lines.
+func LoadPatterns(r io.Reader) ([]string, error) {
+	var pattern []string
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		line := strings.TrimSuffix(scan.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pattern = append(pattern, line)
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	if len(pattern) == 0 {
+		return nil, ErrNoPatterns(true)
+	}
+	return pattern, nil
 }
 
 // MatchGlob returns the result of calling Match with the given string pattern
 // used to match file names according to path.Match semantics.
 func MatchGlob(option Option, pattern string, sub ...string) ([]string, error) {
+	return MatchGlobWithContext(context.Background(), option, pattern, sub...)
+}
+
+// MatchGlobWithContext returns the result of calling MatchWithContext with the
+// given string pattern used to match file names according to path.Match
+// semantics.
+func MatchGlobWithContext(ctx context.Context, option Option, pattern string, sub ...string) ([]string, error) {
 	option.Expr = expr.Glob
 	if option.IgnoreCase {
 		pattern = strings.ToLower(pattern)
 	}
+	return MatchWithContext(ctx, option, pattern, sub...)
+}
+
+// MatchGlobList returns the result of calling MatchAny with the given list of
+// string patterns used to match file names according to path.Match semantics.
+// The list is sorted and duplicate patterns are removed prior to searching.
+func MatchGlobList(option Option, patterns []string, sub ...string) ([]string, error) {
+	pattern := append([]string{}, patterns...)
+	sort.Strings(pattern)
+	n := 0
+	for i, p := range pattern {
+		if i == 0 || p != pattern[n-1] {
+			pattern[n] = p
+			n++
+		}
+	}
+	option.Expr = expr.Glob
+	return MatchAny(option, pattern[:n], sub...)
+}
+
+// MatchGlobMulti returns the result of calling MatchAny with each of the
+// comma-separated string patterns in the given list patterns, with surrounding
+// whitespace removed, used to match file names according to path.Match
+// semantics (e.g., "*.go, *.mod, *.sum").
+// Patterns containing a comma cannot be specified in the list.
+func MatchGlobMulti(option Option, patterns string, sub ...string) ([]string, error) {
+	var pattern []string
+	for _, p := range strings.Split(patterns, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			pattern = append(pattern, p)
+		}
+	}
+	option.Expr = expr.Glob
+	return MatchAny(option, pattern, sub...)
+}
+
+// MatchGlobCI returns the result of calling Match with the given string pattern
+// used to match file names according to path.Match semantics, ignoring case.
+// Unlike MatchGlob with option.IgnoreCase, neither the given pattern nor any
+// file name is modified; only the values compared are converted to lower case.
+func MatchGlobCI(option Option, pattern string, sub ...string) ([]string, error) {
+	option.Expr = expr.Glob
+	option.IgnoreCase = false
+	option.matcher = globCI
 	return Match(option, pattern, sub...)
 }
 
+// globCI reports whether the given file name matches the given string pattern
+// according to path.Match semantics, ignoring case.
+func globCI(pattern, name string) (bool, error) {
+	return path.Match(strings.ToLower(pattern), strings.ToLower(name))
+}
+
 // MatchRegexp returns the result of calling Match with the given string pattern
 // used to match file names according to regexp.Regexp semantics.
 func MatchRegexp(option Option, pattern string, sub ...string) ([]string, error) {
+	return MatchRegexpWithContext(context.Background(), option, pattern, sub...)
+}
+
+// MatchRegexpWithContext returns the result of calling MatchWithContext with
+// the given string pattern used to match file names according to regexp.Regexp
+// semantics.
+func MatchRegexpWithContext(ctx context.Context, option Option, pattern string, sub ...string) ([]string, error) {
 	option.Expr = expr.Regexp
 	if option.IgnoreCase {
 		pattern = "(?i)" + pattern
 	}
-	return Match(option, pattern, sub...)
+	return MatchWithContext(ctx, option, pattern, sub...)
+}
+
+// MatchAny returns the union of all files matching any of the given string
+// patterns, according to the semantics of option.Expr.
+//
+// Each directory is searched only once, testing each file against all patterns,
+// so that each file is reported only once, even if it matches multiple
+// patterns. Files are reported in the order they are found, regardless of the
+// order of the patterns. All patterns are validated before searching.
+func MatchAny(option Option, pattern []string, sub ...string) ([]string, error) {
+	var found []string
+	err := matchAny(context.Background(), option, pattern, sub, func(r Result) error {
+		found = append(found, r.String())
+		return nil
+	})
+	return found, err
+}
+
+// matchAny walks each of the given directories sub once, calling visit with the
+// Result of each file whose name matches any of the given string patterns
+// according to option.Expr.
+func matchAny(ctx context.Context, option Option, pattern []string, sub []string, visit visitFunc) error {
+	fold := make([]string, len(pattern))
+	for i, p := range pattern {
+		fold[i] = option.fold(p)
+	}
+	m, err := option.anyMatcher(fold)
+	if err != nil {
+		return err
+	}
+	option.matcher = m
+	return match(ctx, option, "", sub, visit)
+}
+
+// anyMatcher returns a matchFunc reporting whether a file name matches any of
+// the given patterns, or an error if any pattern is invalid for option.Expr.
+// The pattern given to the returned matchFunc is ignored.
+func (option Option) anyMatcher(pattern []string) (matchFunc, error) {
+	if option.matcher == nil && option.searchable() {
+		return option.algoMatcher(pattern...), nil
+	}
+	if option.matcher == nil && option.Expr == expr.BraceExpr {
+		return option.braceMatcher(pattern...), nil
+	}
+	one := option.matchName
+	switch {
+	case option.matcher != nil:
+	case option.Expr == expr.Glob:
+		for _, p := range pattern {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, err
+			}
+		}
+	case option.Expr == expr.Regexp:
+		for _, err := range expr.Precompile(pattern) {
+			if err != nil {
+				return nil, err
+			}
+		}
+	case option.Expr == expr.Fuzzy:
+		one = option.fuzzyMatcher()
+	}
+	return func(_, name string) (bool, error) {
+		for _, p := range pattern {
+			if ok, err := one(p, name); ok || err != nil {
+				return ok, err
+			}
+		}
+		return false, nil
+	}, nil
+}
+
+// matchFunc is the signature of a function reporting whether a file name
+// matches a string pattern.
+type matchFunc func(pattern, name string) (bool, error)
+
+// resultFunc is the signature of a function reporting whether a Result of the
+// search satisfies some condition.
+type resultFunc func(r Result) (bool, error)
+
+// matchName reports whether the given file name matches the given string
+// pattern according to option.Expr, unless overridden by option.matcher.
+func (option Option) matchName(pattern, name string) (bool, error) {
+	if option.matcher != nil {
+		return option.matcher(pattern, name)
+	}
+	return option.Expr.Match(pattern, name)
+}
+
+// abs returns an absolute representation of the given path p, which is
+// interpreted relative to option.WorkingDir if set, or otherwise the current
+// working directory. The given path is returned unmodified if an absolute
+// representation cannot be determined.
+func (option Option) abs(p string) string {
+	if option.WorkingDir != "" && !filepath.IsAbs(p) {
+		p = filepath.Join(option.WorkingDir, p)
+	}
+	if a, err := filepath.Abs(p); err == nil {
+		return a
+	}
+	return p
+}
+
+// relative returns a representation of the given path p relative to
+// option.RelativeTo, with both interpreted relative to option.WorkingDir if set.
+// The absolute representation of p is returned if it cannot be made relative.
+func (option Option) relative(p string) string {
+	a := option.abs(p)
+	if r, err := filepath.Rel(option.abs(option.RelativeTo), a); err == nil {
+		return r
+	}
+	return a
+}
+
+// fold returns the given string pattern modified such that it will match file
+// names regardless of case if option.IgnoreCase is true.
+func (option Option) fold(pattern string) string {
+	if option.IgnoreCase {
+		if option.Expr == expr.Regexp {
+			return "(?i)" + pattern
+		}
+		return strings.ToLower(pattern)
+	}
+	return pattern
 }
 
 // ErrMaxDepth represents a condition when walking a file system where the
@@ -82,6 +408,32 @@ func (e ErrWalkDir) Error() string {
 	return "{" + strings.Join(t, ", ") + "}"
 }
 
+// Unwrap returns each error in the receiver ErrWalkDir e, so that errors.Is and
+// errors.As can be used to distinguish a canceled search (e.g., context.Canceled
+// or context.DeadlineExceeded) from a failure reading a directory.
+func (e ErrWalkDir) Unwrap() []error {
+	u := make([]error, len(e))
+	for i, s := range e {
+		u[i] = s.err
+	}
+	return u
+}
+
+// ErrMaxDirs represents an error in which a search was stopped after searching
+// the maximum number of directories allowed.
+type ErrMaxDirs int
+
+// Error returns a descriptive error string for the receiver ErrMaxDirs e.
+func (e ErrMaxDirs) Error() string {
+	return "maximum directories searched: " + strconv.Itoa(int(e))
+}
+
+// exceedsMaxDirs reports whether the search has attempted to search more than
+// option.MaxDirs directories.
+func (option Option) exceedsMaxDirs() bool {
+	return option.dirs != nil && atomic.LoadInt64(option.dirs) > int64(option.MaxDirs)
+}
+
 // ErrInvalidPath represents an error for a path with invalid symbols.
 type ErrInvalidPath string
 
@@ -90,6 +442,56 @@ func (e ErrInvalidPath) Error() string {
 	return "invalid path: " + string(e)
 }
 
+// ErrSymlinkLoop represents a condition when dereferencing a symlink where the
+// length of the resulting Chain is greater than maximum allowed, which is also
+// the case for any Chain containing a loop.
+type ErrSymlinkLoop string
+
+// Error returns a descriptive error string for the receiver ErrSymlinkLoop e.
+func (e ErrSymlinkLoop) Error() string {
+	return "maximum symlink chain length exceeded: " + string(e)
+}
+
+// ErrSymlinkCycle represents a condition when following a symlink that refers to
+// a directory containing the symlink itself, which would otherwise be searched
+// repeatedly until MaxDepth or MaxFollow is exceeded. Such a symlink is never
+// followed, and the error is given to Option.WalkErrHandler, if non-nil, to
+// decide whether or not to continue searching.
+type ErrSymlinkCycle string
+
+// Error returns a descriptive error string for the receiver ErrSymlinkCycle e.
+func (e ErrSymlinkCycle) Error() string {
+	return "symlink refers to a containing directory: " + string(e)
+}
+
+// ErrConflictingOptions represents an error in which the named Option fields
+// were given values that cannot be used together.
+type ErrConflictingOptions []string
+
+// Error returns a descriptive error string for the receiver
+// ErrConflictingOptions e.
+func (e ErrConflictingOptions) Error() string {
+	return "conflicting options: " + strings.Join(e, ", ")
+}
+
+// ErrNoPatterns represents an error in which no patterns were read from a list
+// of patterns.
+type ErrNoPatterns bool
+
+// Error returns a descriptive error string for the receiver ErrNoPatterns e.
+func (ErrNoPatterns) Error() string {
+	return "no patterns found"
+}
+
+// ErrInvalidOption represents an error in which the named Option field was
+// given an invalid value.
+type ErrInvalidOption string
+
+// Error returns a descriptive error string for the receiver ErrInvalidOption e.
+func (e ErrInvalidOption) Error() string {
+	return "invalid option: " + string(e)
+}
+
 // ValidPath reports whether the given string s contains invalid symbols for a
 // file path.
 func ValidPath(s string) error {
@@ -102,7 +504,8 @@ func ValidPath(s string) error {
 		return r
 	}
 	// Note this has different semantics than strings.ContainsAny(s, ignore).
-	if !fs.ValidPath(strings.Map(strip, s)) {
+	// A path containing only ignored runes (e.g., "." or "/") is valid.
+	if t := strings.Map(strip, s); s == "" || t != "" && !fs.ValidPath(t) {
 		return ErrInvalidPath(s)
 	}
 	return nil
@@ -116,6 +519,7 @@ type (
 		root string
 		name string
 		ent  fs.DirEntry
+		fsys fs.FS // nil refers to the host operating system's file system
 	}
 )
 
@@ -137,25 +541,27 @@ func (c *Chain) Head() *Link {
 	return nil
 }
 
-// String returns a graphical representation of a Chain.
+// Tail returns the last Link in a Chain, which is the file ultimately referred
+// to by all preceding symlinks.
+func (c *Chain) Tail() *Link {
+	if len(*c) > 0 {
+		return (*c)[len(*c)-1]
+	}
+	return nil
+}
+
+// String returns a graphical representation of a Chain using UnicodeFormatter.
 func (c *Chain) String() string {
-	if len(*c) == 0 {
-		return ""
-	} else if len(*c) == 1 {
-		return (*c)[0].Path()
-	} else {
-		var sb strings.Builder
-		for i := 0; i < len(*c); i++ {
-			branch := "└┬╼╸"
-			if i == 0 {
-				branch = "─┬╼╸"
-			} else if i == len(*c)-1 {
-				branch = "└─╼╸"
-			}
-			fmt.Fprintf(&sb, "%*s%s %s\n", i, "", branch, (*c)[i].Path())
-		}
-		return sb.String()
+	return c.StringWith(UnicodeFormatter{})
+}
+
+// StringWith returns a representation of a Chain using the given formatter f.
+func (c *Chain) StringWith(f ChainFormatter) string {
+	link := make([]Link, len(*c))
+	for i, l := range *c {
+		link[i] = *l
 	}
+	return f.Format(link)
 }
 
 // NewLink returns a reference to a new Link, initialized with the given file
@@ -175,143 +581,449 @@ func (l *Link) IsSymlink() bool { return l.ent.Type()&fs.ModeSymlink != 0 }
 // file system attributes of the receive symlink.
 func (l *Link) Deref() (d Link, err error) {
 	var dest string
-	dest, err = os.Readlink(l.Path())
+	dest, err = l.readlink()
 	if err != nil {
 		return // Just ignore the symlink if there is any error.
 	}
 	if !path.IsAbs(dest) {
-		dest = path.Join(l.root, dest)
+		dest = path.Join(path.Dir(l.Path()), dest)
 	}
 	var info fs.FileInfo
-	info, err = os.Lstat(dest)
+	info, err = l.lstat(dest)
 	if err != nil {
 		return // Just ignore the symlink if there is any error.
 	}
 	d.root = path.Dir(dest)
 	d.name = path.Base(dest)
 	d.ent = fs.FileInfoToDirEntry(info)
+	d.fsys = l.fsys
 	return
 }
 
-func Match(option Option, pattern string, sub ...string) (found []string, err error) {
+// Match returns a list of all files found in each of the given directories sub
+// whose name matches the given string pattern according to option.Expr.
+//
+// Deprecated: Match cannot be canceled. Use MatchWithContext instead.
+func Match(option Option, pattern string, sub ...string) ([]string, error) {
+	return MatchWithContext(context.Background(), option, pattern, sub...)
+}
+
+// MatchWithContext returns a list of all files found in each of the given
+// directories sub whose name matches the given string pattern according to
+// option.Expr, sorted by option.SortBy if set.
+//
+// The search stops as soon as the given context ctx is done, and the error
+// returned by ctx.Err() is included in the returned ErrWalkDir.
+func MatchWithContext(ctx context.Context, option Option, pattern string, sub ...string) (found []string, err error) {
+	if option.SortBy != "" {
+		// All files must be found before they can be sorted.
+		var res []Result
+		res, err = matchResults(ctx, option, pattern, sub)
+		for _, r := range res {
+			found = append(found, r.String())
+		}
+		return
+	}
+	err = match(ctx, option, pattern, sub, func(r Result) error {
+		found = append(found, r.String())
+		return nil
+	})
+	return
+}
+
+// Count returns the number of files found in each of the given directories sub
+// whose name matches the given string pattern according to option.Expr, which
+// is the number of files Match would return, but without allocating the path
+// of each file.
+func Count(option Option, pattern string, sub ...string) (int, error) {
+	n := 0
+	err := match(context.Background(), option, pattern, sub, func(Result) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// visitFunc is the signature of the function called by match for each file
+// whose name matches the pattern.
+type visitFunc func(r Result) error
+
+// match walks each of the given directories sub, calling visit with the Result
+// of each file whose name matches the given string pattern according to
+// option.Expr.
+func match(ctx context.Context, option Option, pattern string, sub []string, visit visitFunc) error {
+
+	if err := option.Validate(); err != nil {
+		return err
+	}
+
+	if option.MaxResults > 0 {
+		return matchLimit(ctx, option, pattern, sub, visit)
+	}
+
+	if option.Unique {
+		return matchUnique(ctx, option, pattern, sub, visit)
+	}
+
+	if option.searchable() && option.matcher == nil {
+		option.matcher = option.algoMatcher(pattern)
+	}
+
+	if option.Expr == expr.Fuzzy && option.matcher == nil {
+		option.matcher = option.fuzzyMatcher()
+	}
+
+	if option.Expr == expr.BraceExpr && option.matcher == nil {
+		option.matcher = option.braceMatcher(pattern)
+	}
+
+	if option.GitStagedOnly && option.staged == nil && option.fsys == nil {
+		var err error
+		if option.staged, err = gitStaged(ctx, option.abs(".")); err != nil {
+			return err
+		}
+	}
+
+	// The number of directories searched is shared by all recursive calls.
+	top := option.MaxDirs > 0 && option.dirs == nil
+	if top {
+		option.dirs = new(int64)
+	}
 
 	serr := make(ErrWalkDir, 0, len(sub))
 
-	for _, p := range sub {
+	if option.Parallel > 1 && len(sub) > 1 {
+		serr = option.matchParallel(ctx, pattern, sub, visit)
+	} else {
+		for _, p := range sub {
+			root := option.root(p)
 
-		// A canonical path is required for accurately computing traversal depth.
-		root := path.Clean(p)
+			if cerr := ctx.Err(); cerr != nil {
+				// Stop all processing if the context is done.
+				serr = append(serr, errWalkDir{dir: root, err: cerr})
+				break
+			}
 
-		werr := fs.WalkDir(os.DirFS(root), ".",
-			func(c string, d fs.DirEntry, err error) error {
+			if werr := option.matchRoot(ctx, pattern, root, visit); werr != nil {
+				serr = append(serr, errWalkDir{dir: root, err: werr})
+				if ctx.Err() != nil {
+					break // Do not search remaining directories if the context is done.
+				}
+			}
+			if option.exceedsMaxDirs() {
+				break // Do not search remaining directories if MaxDirs was reached.
+			}
+		}
+	}
 
-				// Check if we have an error on directory entry
-				if err != nil {
-					if d == nil {
-						// The root path os.DirFS(p) was invalid; stop all processing.
-						return err
-					} else {
-						// os.ReadDir(path) failed; skip the directory.
-						return nil
-					}
+	if top && option.exceedsMaxDirs() {
+		return ErrMaxDirs(option.MaxDirs)
+	}
+
+	// Ensure the returned error is nil unless we have added elements to serr.
+	if len(serr) > 0 {
+		return serr
+	}
+	return nil
+}
+
+// root returns the canonical form of the given search directory p, which is
+// required for accurately computing traversal depth.
+func (option Option) root(p string) string {
+	root := path.Clean(p)
+	if option.AbsolutePaths && option.fsys == nil {
+		root = option.abs(root)
+	}
+	return root
+}
+
+// matchRoot walks the given search directory root, calling visit with the
+// Result of each file whose name matches the given string pattern according to
+// option.Expr.
+func (option Option) matchRoot(ctx context.Context, pattern, root string, visit visitFunc) error {
+
+	fsys, werr := option.sub(root)
+	if werr != nil {
+		return werr
+	}
+
+	// Files reachable by symlinks are filtered by the set of the original root.
+	if option.GitTrackedOnly && option.fsys == nil && option.fromFollow == 0 {
+		if option.tracked, werr = gitTracked(ctx, option.abs(root)); werr != nil {
+			return werr
+		}
+	}
+
+	// Device ID of each directory searched, used to detect mount points.
+	devs := map[string]uint64{}
+	// Depth of each Go module root directory searched, if GoModuleAware.
+	mods := map[string]int{}
+	// Identity of each directory searched, used to detect symlink cycles.
+	ids := map[string]fileID{}
+
+	return option.walkDir(fsys, ".",
+		func(c string, d fs.DirEntry, err error) error {
+
+			// Stop all processing if the context is done.
+			if cerr := ctx.Err(); cerr != nil {
+				return cerr
+			}
+
+			// Check if we have an error on directory entry
+			if err != nil {
+				option.trace(TraceSkip, path.Join(root, c), option.fromDepth, err.Error())
+				if option.WalkErrHandler != nil {
+					// Let the caller decide whether to continue, skip, or stop.
+					return option.WalkErrHandler(path.Join(root, c), err)
+				}
+				if d == nil {
+					// The root path os.DirFS(p) was invalid; stop all processing.
+					return err
+				} else {
+					// os.ReadDir(path) failed; skip the directory.
+					return nil
 				}
+			}
 
-				chain := MakeChain(NewLink(root, c, d))
+			head := NewLink(root, c, d)
+			head.fsys = option.fsys
+			chain := MakeChain(head)
 
-				// Before recursing down a directory, verify we won't exceed MaxDepth
-				depth := len(strings.FieldsFunc(strings.TrimPrefix(chain.Head().Path(), root),
-					func(r rune) bool { return r == os.PathSeparator })) + option.fromDepth
-				//fmt.Printf("[%d] %s // %s\n", depth, root, c)
-				if d.IsDir() && depth >= option.MaxDepth {
-					// Stop processing this subtree if it exceeds MaxDepth.
-					return fs.SkipDir
+			// Before recursing down a directory, verify we won't exceed MaxDepth
+			depth := len(strings.FieldsFunc(strings.TrimPrefix(chain.Head().Path(), root),
+				func(r rune) bool { return r == os.PathSeparator })) + option.fromDepth
+			// Count depth from the nearest enclosing Go module root, if any.
+			module := option.module
+			if option.GoModuleAware {
+				if d.IsDir() {
+					if _, serr := fs.Stat(fsys, path.Join(c, "go.mod")); serr == nil {
+						mods[c] = depth
+					}
+				}
+				for p := c; ; p = path.Dir(p) {
+					if base, ok := mods[p]; ok {
+						module, depth = path.Join(root, p), depth-base
+						break
+					}
+					if p == "." {
+						break
+					}
+				}
+			}
+			option := option
+			option.module = module
+			// skipDir stops processing the current subtree for the given reason,
+			// after testing if the directory itself matches.
+			skipDir := func(reason string) error {
+				option.trace(TraceSkip, chain.Head().Path(), depth, reason)
+				if c != "." && option.typed(chain.Head(), d) {
+					if err := option.test(pattern, root, chain, d, depth, visit); err != nil {
+						return err
+					}
+				}
+				return fs.SkipDir
+			}
+			if d.IsDir() && depth >= option.MaxDepth {
+				// Stop processing this subtree if it exceeds MaxDepth.
+				return skipDir("exceeds MaxDepth")
+			}
+			if d.IsDir() && c != "." && !option.descend(d) {
+				// Stop processing this subtree if it is not an allowed directory.
+				return skipDir("directory not allowed")
+			}
+			if d.IsDir() && option.SkipMountPoints {
+				if info, ierr := d.Info(); ierr == nil {
+					if dev, ok := device(info); ok {
+						if pdev, ok := devs[path.Dir(c)]; ok && c != "." && dev != pdev {
+							// Stop processing this subtree if it is on another device.
+							return skipDir("mount point")
+						}
+						devs[c] = dev
+					}
+				}
+			}
+			if d.IsDir() && option.MaxDirs > 0 {
+				if atomic.AddInt64(option.dirs, 1) > int64(option.MaxDirs) {
+					// Stop all processing once MaxDirs directories have been searched.
+					option.trace(TraceSkip, chain.Head().Path(), depth, "exceeds MaxDirs")
+					return fs.SkipAll
+				}
+			}
+			if d.IsDir() {
+				option.trace(TraceDir, chain.Head().Path(), depth, "")
+				option.Stats.addDir()
+			}
+			if d.IsDir() && option.FollowSymlinks {
+				if info, ierr := d.Info(); ierr == nil {
+					if id, ok := identify(chain.Head().Path(), info); ok {
+						ids[c] = id
+					}
 				}
+			}
 
-				// Special processing for symlinks if we should follow them.
-				if option.FollowSymlinks && chain.Head().IsSymlink() {
+			// Special processing for symlinks if we should follow them, unless they
+			// should be matched as regular files.
+			if option.FollowSymlinks && !option.SymlinksAsFiles && chain.Head().IsSymlink() {
 
-					ptr := chain.Head()
+				ptr := chain.Head()
+				option.trace(TraceSymlink, ptr.Path(), depth, "")
 
-					// Repeatedly dereference the symlink until we have a regular file.
-					for {
-						dest, err := ptr.Deref()
-						if err != nil {
-							return nil // Just ignore the symlink if there is any error.
-						}
-						chain.Add(&dest)
-						ptr = &dest
-						if !ptr.IsSymlink() {
-							break // Dereferenced file is not a symlink; stop dereferencing.
+				// Repeatedly dereference the symlink until we have a regular file.
+				for {
+					if cerr := ctx.Err(); cerr != nil {
+						return cerr // Stop all processing if the context is done.
+					}
+					dest, err := ptr.Deref()
+					if err != nil {
+						if option.DanglingOnly && errors.Is(err, fs.ErrNotExist) {
+							break // Symlink is broken; test if it matches as-is.
 						}
+						return nil // Just ignore the symlink if there is any error.
+					}
+					chain.Add(&dest)
+					if option.MaxSymlinkChainLength > 0 &&
+						len(chain) > option.MaxSymlinkChainLength {
+						// Stop processing if the chain is too long (or is a loop).
+						return ErrSymlinkLoop(chain.Head().Path())
 					}
+					ptr = &dest
+					if !ptr.IsSymlink() {
+						break // Dereferenced file is not a symlink; stop dereferencing.
+					}
+				}
+
+				// At this point, chain.Head() refers to the original symlink, and ptr
+				// refers to the regular file/dir to which it linked (directly or
+				// indirectly, in the case of nested symlinks).
 
-					// At this point, chain.Head() refers to the original symlink, and ptr
-					// refers to the regular file/dir to which it linked (directly or
-					// indirectly, in the case of nested symlinks).
-
-					// Check if symlink referred to a directory.
-					if ptr.ent.IsDir() {
-						// Regardless of the number of indirections, we consider it having
-						// recursed only 1 level. Verify that it doesn't exceed MaxDepth.
-						if depth+1 <= option.MaxDepth {
-							// Copy our existing Options, and update traversal counters so
-							// that the recursive call to Match can accurately keep track
-							// (which can not be computed by simply counting the number
-							// of directories between our Walk root and current descendent).
-							//
-							// This only modifies the copied Options struct;
-							//   the Options from the caller's context remain unmodified.
-							lopt := option
-							lopt.fromDepth = depth
-							// Stop following symlinks as soon as we exceed MaxFollow.
-							lopt.fromFollow++
-							lopt.FollowSymlinks = lopt.fromFollow < lopt.MaxFollow ||
-								lopt.MaxFollow < 0 // Negative = unlimited dereferences
-
-							mfound, merr := Match(lopt, pattern, ptr.Path())
-							// Just ignore the symlink if there is an error of any sort.
-							if merr == nil {
-								found = append(found, mfound...)
+				// Check if symlink referred to a directory.
+				if ptr.ent.IsDir() {
+					// Do not follow a symlink referring to any directory containing it.
+					follow := true
+					ancestors := option.ancestorsOf(ids, c)
+					if info, ierr := ptr.ent.Info(); ierr == nil {
+						if id, ok := identify(ptr.Path(), info); ok && containsID(ancestors, id) {
+							option.trace(TraceSkip, chain.Head().Path(), depth, "symlink cycle")
+							if option.WalkErrHandler != nil {
+								herr := option.WalkErrHandler(chain.Head().Path(), ErrSymlinkCycle(chain.Head().Path()))
+								if herr != nil {
+									return herr
+								}
 							}
+							follow = false
 						}
 					}
+					// Regardless of the number of indirections, we consider it having
+					// recursed only 1 level. Verify that it doesn't exceed MaxDepth.
+					if follow && depth+1 <= option.MaxDepth && option.descend(d) {
+						// Copy our existing Options, and update traversal counters so
+						// that the recursive call to Match can accurately keep track
+						// (which can not be computed by simply counting the number
+						// of directories between our Walk root and current descendent).
+						//
+						// This only modifies the copied Options struct;
+						//   the Options from the caller's context remain unmodified.
+						lopt := option
+						lopt.fromDepth = depth
+						lopt.ancestors = ancestors
+						// Stop following symlinks as soon as we exceed MaxFollow.
+						lopt.fromFollow++
+						lopt.FollowSymlinks = lopt.fromFollow < lopt.MaxFollow ||
+							lopt.MaxFollow < 0 // Negative = unlimited dereferences
 
-					// Update our DirEntry and current path to refer to our dereferenced
-					// file/directory.
-					d = ptr.ent
-					c = ptr.Path()
-				}
-
-				// Finally, if current file is not a directory, test if it matches the
-				// user-provided pattern.
-				if !d.IsDir() {
-					base := path.Base(chain.Head().name)
-					if option.IgnoreCase {
-						base = strings.ToLower(base)
-					}
-					ok, merr := option.Expr.Match(pattern, base)
-					if merr != nil {
-						// If there was an error with matching, stop processing completely
-						// because the pattern is invalid.
-						return merr
-					} else if ok {
-						// No error, add the current chain to our list of matches.
-						found = append(found, chain.String())
+						// Just ignore the symlink if there is an error of any sort, unless
+						// the context is done, in which case stop all processing.
+						merr := match(ctx, lopt, pattern, []string{ptr.Path()}, visit)
+						if cerr := ctx.Err(); merr != nil && cerr != nil {
+							return cerr
+						}
 					}
 				}
 
-				// Continue processing.
-				return nil
-			})
+				// Update our DirEntry and current path to refer to our dereferenced
+				// file/directory.
+				d = ptr.ent
+				c = ptr.Path()
+			}
+
+			// Finally, if current file is one of the requested types (other than the
+			// search directory itself), test if it matches the user-provided pattern.
+			if (c != "." || !d.IsDir()) && option.typed(chain.Head(), d) {
+				return option.test(pattern, root, chain, d, depth, visit)
+			}
+			// Continue processing.
+			return nil
+		})
+}
+
+// fileID identifies a file independently of the path by which it was found.
+type fileID struct {
+	dev, ino uint64 // Device ID and inode number, if reported by the platform
+	path     string // Path after resolving all symlinks, if not
+}
 
-		if werr != nil {
-			serr = append(serr, errWalkDir{dir: root, err: werr})
+// ancestorsOf returns the fileID of each directory containing the file at the
+// given path c relative to the search directory, given the fileID of each
+// directory searched ids, including those containing the search directory.
+func (option Option) ancestorsOf(ids map[string]fileID, c string) []fileID {
+	ancestors := append([]fileID{}, option.ancestors...)
+	for p := path.Dir(c); ; p = path.Dir(p) {
+		if id, ok := ids[p]; ok {
+			ancestors = append(ancestors, id)
+		}
+		if p == "." {
+			return ancestors
 		}
 	}
+}
 
-	// Ensure the returned error is nil unless we have added elements to serr.
-	if len(serr) > 0 {
-		return found, serr
+// containsID reports whether the given fileID id is an element of ids.
+func containsID(ids []fileID, id fileID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// test visits the given file, found at the given depth below the given search
+// directory root, if its name matches the given string pattern and it satisfies
+// all other conditions of the receiver Option option.
+func (option Option) test(pattern, root string, chain Chain, d fs.DirEntry, depth int, visit visitFunc) error {
+	base := path.Base(chain.Head().name)
+	if option.IgnoreCase {
+		base = strings.ToLower(base)
+	}
+	ok, merr := option.matchName(pattern, base)
+	if merr != nil {
+		// If there was an error with matching, stop processing completely
+		// because the pattern is invalid.
+		return merr
+	} else if ok && option.accept(chain, d) {
+		// No error, visit the current chain.
+		r := Result{Path: chain.Head().Path(), Root: root, Depth: depth,
+			Chain: chain, MatchedPattern: pattern, Module: option.module, ent: d}
+		if option.RelativeTo != "" && option.fsys == nil {
+			r.Path, r.rel = option.relative(r.Path), option.relative
+		}
+		if option.predicate != nil {
+			if pok, perr := option.predicate(r); perr != nil {
+				return perr
+			} else if !pok {
+				option.trace(TraceSkip, r.Path, depth, "rejected by predicate")
+				return nil
+			}
+		}
+		if verr := visit(r); verr != nil {
+			return verr
+		}
+		option.trace(TraceMatch, r.Path, depth, "")
+		option.Stats.addMatch()
+	} else if ok {
+		option.trace(TraceSkip, chain.Head().Path(), depth, "rejected by filter")
+	} else {
+		option.trace(TraceFile, chain.Head().Path(), depth, "")
 	}
-	return found, nil
+	return nil
 }
@@ -0,0 +1,237 @@
+package wh
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/ardnew/wh/expr"
+)
+
+// makeTree creates each of the given files, with its content, relative to a new
+// temporary directory, which is returned. Parent directories are created as
+// needed, and a name ending with "/" creates an empty directory.
+func makeTree(t testing.TB, file map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range file {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if name[len(name)-1] == '/' {
+			if err := os.MkdirAll(p, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// join returns each of the given names joined to the given directory dir, in
+// the form reported by Match.
+func join(dir string, name ...string) []string {
+	p := make([]string, len(name))
+	for i, n := range name {
+		p[i] = filepath.Join(dir, filepath.FromSlash(n))
+	}
+	return p
+}
+
+// expect fails the test if the given error err is non-nil, or if the given
+// files found differ from those in want.
+func expect(t *testing.T, found []string, err error, want ...string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(found, want) {
+		t.Fatalf("found %q, want %q", found, want)
+	}
+}
+
+// chdir changes the current working directory to the given directory dir until
+// the test completes.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestAbsolutePaths(t *testing.T) {
+	dir := makeTree(t, map[string]string{"sub/a.txt": ""})
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, dir)
+	option := DefaultOption()
+	option.AbsolutePaths = true
+	for _, root := range []string{"sub", "./sub", "sub/", filepath.Join("..", filepath.Base(dir), "sub")} {
+		found, err := MatchFixed(option, "a.txt", root)
+		expect(t, found, err, join(dir, "sub/a.txt")...)
+		if !filepath.IsAbs(found[0]) {
+			t.Fatalf("MatchFixed(%q) = %q, want absolute path", root, found)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"a.go": "", "b.go": "", "c.txt": "", "sub/d.go": "", "sub/e.txt": "",
+	})
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	for _, depth := range []int{0, 1, 2} {
+		option.MaxDepth = depth
+		for _, pattern := range []string{"*.go", "*.txt", "*", "none"} {
+			found, err := Match(option, pattern, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, err := Count(option, pattern, dir)
+			if err != nil || n != len(found) {
+				t.Errorf("MaxDepth %d: Count(%q) = %d, %v, want %d",
+					depth, pattern, n, err, len(found))
+			}
+		}
+	}
+}
+
+func BenchmarkCount(b *testing.B) {
+	option := DefaultOption()
+	option.Expr = expr.Glob
+	dir := b.TempDir()
+	for i := 0; i < 100; i++ {
+		if err := os.WriteFile(filepath.Join(dir, strconv.Itoa(i)), nil, 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.Run("Match", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Match(option, "*", dir)
+		}
+	})
+	b.Run("Count", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Count(option, "*", dir)
+		}
+	})
+}
+
+func TestMatchGlobVariants(t *testing.T) {
+	dir := makeTree(t, map[string]string{"a.go": "", "B.GO": "", "c.mod": "", "d.sum": ""})
+	option := DefaultOption()
+
+	found, err := MatchGlobList(option, []string{"*.sum", "*.go", "*.sum", "*.go"}, dir)
+	expect(t, found, err, join(dir, "a.go", "d.sum")...)
+	found, err = MatchGlobMulti(option, " *.go, ,*.mod ", dir)
+	expect(t, found, err, join(dir, "a.go", "c.mod")...)
+	found, err = MatchGlobCI(option, "*.Go", dir)
+	expect(t, found, err, join(dir, "B.GO", "a.go")...)
+	if _, err := MatchGlobList(option, []string{"["}, dir); err == nil {
+		t.Fatal("MatchGlobList([\"[\"]) = nil error")
+	}
+}
+
+func TestMatchFixedFile(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"a.go": "", "b.go": "", "c.go": "",
+		"patterns":       "# comment\r\na.go\n\nc.go\r\n",
+		"empty/patterns": "# nothing\n\n",
+	})
+	option := DefaultOption()
+	option.Exclude = []string{"patterns"}
+	found, err := MatchFixedFile(option, join(dir, "patterns")[0], dir)
+	expect(t, found, err, join(dir, "a.go", "c.go")...)
+	if _, err := MatchFixedFile(option, join(dir, "empty/patterns")[0], dir); err != ErrNoPatterns(true) {
+		t.Fatalf("MatchFixedFile(empty) error = %v, want ErrNoPatterns", err)
+	}
+	if _, err := MatchFixedFile(option, join(dir, "missing")[0], dir); !os.IsNotExist(err) {
+		t.Fatalf("MatchFixedFile(missing) error = %v, want not exist", err)
+	}
+}
+
+func TestErrorStrings(t *testing.T) {
+	for _, err := range []error{
+		ErrMaxDepth(3),
+		ErrWalkDir{{dir: "a", err: ErrMaxDirs(2)}},
+		ErrMaxDirs(2),
+		ErrInvalidPath("a/../b"),
+		ErrSymlinkLoop("a"),
+		ErrSymlinkCycle("a"),
+		ErrConflictingOptions{"A", "B"},
+		ErrNoPatterns(true),
+		ErrInvalidOption("A"),
+		ErrNoReadLink(true),
+		ErrUndefinedEnv("A"),
+		ErrInvalidSortKey("A"),
+		ErrFilterExpr{Pos: 1, Msg: "A"},
+		ErrNotGitRepo("a"),
+		ErrNoMatch("a"),
+		ErrNoCommand(true),
+		ErrUnsupportedOption("A"),
+		expr.ErrInvalidExpr(99),
+	} {
+		if err.Error() == "" {
+			t.Errorf("%T.Error() = \"\"", err)
+		}
+	}
+}
+
+func TestGoModuleAware(t *testing.T) {
+	dir := makeTree(t, map[string]string{
+		"x": "", "a/b/x": "",
+		"mod/go.mod": "", "mod/x": "", "mod/p/x": "", "mod/p/q/x": "",
+		"mod/p/sub/go.mod": "", "mod/p/sub/r/x": "", "mod/p/sub/r/s/x": "",
+	})
+	option := DefaultOption()
+	option.MaxDepth = 2
+	found, err := Match(option, "x", dir)
+	expect(t, found, err, join(dir, "mod/x", "x")...)
+
+	// MaxDepth is counted from the nearest directory containing go.mod, so files
+	// in each module are found up to 2 levels below its root, and Module names
+	// that root.
+	option.GoModuleAware = true
+	res, err := MatchResults(option, "x", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type result struct{ Path, Module string }
+	var got []result
+	for _, r := range res {
+		got = append(got, result{r.Path, r.Module})
+	}
+	mod, sub := join(dir, "mod")[0], join(dir, "mod/p/sub")[0]
+	want := []result{
+		{join(dir, "mod/p/sub/r/x")[0], sub},
+		{join(dir, "mod/p/x")[0], mod},
+		{join(dir, "mod/x")[0], mod},
+		{join(dir, "x")[0], ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MatchResults() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package wh
+
+import (
+	"archive/zip"
+	"context"
+	"path"
+)
+
+// MatchZip returns a list of all files in the ZIP archive at the given path
+// whose name matches the given string pattern according to option.Expr.
+// Each file returned is the path of the file within the archive, joined to the
+// given path of the archive itself (e.g., "dist/app.zip/bin/app").
+//
+// If option.ContentPattern is set, each file whose name matches is read from
+// the archive and reported only if it contains a line matching the pattern.
+// Symbolic links stored in the archive are never followed.
+func MatchZip(option Option, pattern string, archive string) ([]string, error) {
+	res, err := MatchZipResults(option, pattern, archive)
+	var found []string
+	for _, r := range res {
+		found = append(found, r.Path)
+	}
+	return found, err
+}
+
+// MatchZipResults returns the Result of each file in the ZIP archive at the
+// given path whose name matches the given string pattern, as with MatchZip.
+// The Root of each Result is the path of the archive, and its Info is read from
+// the archive's header for the file, which remains valid after the archive is
+// closed. The Results are sorted by option.SortBy if set.
+func MatchZipResults(option Option, pattern string, archive string) ([]Result, error) {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	option.FollowSymlinks = false
+	option.fsys = &r.Reader
+	var found []Result
+	err = match(context.Background(), option, option.fold(pattern), []string{"."},
+		func(res Result) error {
+			p := path.Join(archive, res.Path)
+			found = append(found, Result{
+				Path:           p,
+				Root:           archive,
+				Depth:          res.Depth,
+				Chain:          MakeChain(NewLink(path.Dir(p), path.Base(p), res.ent)),
+				MatchedPattern: res.MatchedPattern,
+				ent:            res.ent,
+			})
+			return nil
+		})
+	if serr := sortResults(found, option.SortBy); serr != nil {
+		return found, serr
+	}
+	return found, err
+}
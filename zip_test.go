@@ -0,0 +1,62 @@
+package wh
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeZip creates a ZIP archive at the given path containing each of the given
+// files with its content.
+func makeZip(t *testing.T, name string, file map[string]string) {
+	t.Helper()
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for n, content := range file {
+		fw, err := w.Create(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchZipResults(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "arc.zip")
+	makeZip(t, archive, map[string]string{"bin/app": "binary", "README": "readme"})
+
+	option := DefaultOption()
+	option.MaxDepth = 2
+	res, err := MatchZipResults(option, "app", archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Path != archive+"/bin/app" || res[0].Root != archive {
+		t.Fatalf("found %+v", res)
+	}
+	info, err := res[0].Info()
+	if err != nil || info.Size() != int64(len("binary")) {
+		t.Fatalf("Info() = %v, %v", info, err)
+	}
+	var sb strings.Builder
+	if err := FormatFileInfo(&sb, "{{.Name}} {{.Size}}", res[0].Path, info); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != "app 6" {
+		t.Fatalf("FormatFileInfo wrote %q", sb.String())
+	}
+
+	found, err := MatchZip(option, "README", archive)
+	expect(t, found, err, archive+"/README")
+}